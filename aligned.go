@@ -0,0 +1,44 @@
+package buffer
+
+import "unsafe"
+
+// DirectIOAlign O_DIRECT 常用的对齐大小
+const DirectIOAlign = 4096
+
+// NewAlignedBytePool 创建起始地址和容量都按 align 对齐的 []byte 池，用于 O_DIRECT 读写
+// align 必须是 2 的幂；校准值按 align 向上取整，所以 cap 总是 align 的整数倍。
+// Put 时起始地址或 cap 不再对齐 (调用方 b[n:] 重新切片过，或 append 扩容到了堆上) 的切片直接丢弃。
+func NewAlignedBytePool(align uint64, opts ...*Option) *Pool[[]byte] {
+	if align == 0 || align&(align-1) != 0 {
+		panic("buffer: align must be a power of two")
+	}
+	aligned := func(b []byte) bool {
+		return cap(b) > 0 &&
+			uint64(uintptr(unsafe.Pointer(unsafe.SliceData(b))))%align == 0 &&
+			uint64(cap(b))%align == 0
+	}
+
+	return New(
+		// make: 多分配 align 字节，再切到对齐边界
+		func(size uint64) []byte {
+			raw := make([]byte, size+align)
+			off := uint64(0)
+			if rem := uint64(uintptr(unsafe.Pointer(unsafe.SliceData(raw)))) % align; rem != 0 {
+				off = align - rem
+			}
+			return raw[off : off : off+size]
+		},
+		func(b []byte) []byte {
+			return b[:0]
+		},
+		// stat: 不对齐的切片返回 cap=0，不进池
+		func(b []byte) (uint64, uint64) {
+			if !aligned(b) {
+				return 0, 0
+			}
+			return uint64(len(b)), uint64(cap(b))
+		},
+		// align 最后设置，不能被调用方 (包括 JSON/环境变量/命令行读入的配置) 覆盖
+		append(opts[:len(opts):len(opts)], Options().SetAlign(align))...,
+	)
+}
@@ -0,0 +1,73 @@
+package buffer
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func isAligned(b []byte, align uint64) bool {
+	return uint64(uintptr(unsafe.Pointer(unsafe.SliceData(b))))%align == 0 && uint64(cap(b))%align == 0
+}
+
+// TestAlignedBytePool 测试分配对齐，且校准后依旧对齐
+func TestAlignedBytePool(t *testing.T) {
+	p := NewAlignedBytePool(DirectIOAlign, Options().SetCalibratePeriod(10).SetCalibratedSz(1000))
+
+	for i := 0; i < 100; i++ {
+		b := p.Get()
+		if !isAligned(b, DirectIOAlign) {
+			t.Fatalf("round %d: unaligned buffer, cap %d", i, cap(b))
+		}
+		b = b[:min(cap(b), 5000+i*10)]
+		p.Put(b)
+	}
+	if sz := p.CalibratedSize(); sz%DirectIOAlign != 0 {
+		t.Fatalf("calibrated size %d not a multiple of %d", sz, DirectIOAlign)
+	}
+}
+
+// TestAlignedBytePoolReject 测试重新切片后不再对齐的切片被拒绝
+func TestAlignedBytePoolReject(t *testing.T) {
+	p := NewAlignedBytePool(512)
+
+	b := p.Get()
+	p.Put(b[1:cap(b)]) // 起始地址偏移
+	if p.Stats().Idle != 0 {
+		t.Fatal("unaligned slice should be rejected")
+	}
+	p.Put(b)
+	if p.Stats().Idle != 1 {
+		t.Fatal("aligned slice should be pooled")
+	}
+}
+
+// TestAlignedBytePoolInvalid 测试非法 align
+func TestAlignedBytePoolInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for non power of two align")
+		}
+	}()
+	NewAlignedBytePool(1000)
+}
+
+// TestAlignedBytePoolAlignOverride 测试调用方的 Align 和 Reconfigure 都不能改掉池的对齐
+func TestAlignedBytePoolAlignOverride(t *testing.T) {
+	p := NewAlignedBytePool(DirectIOAlign, Options().SetAlign(512).SetCalibratedSz(4608))
+
+	b := p.Get()
+	if cap(b)%DirectIOAlign != 0 {
+		t.Fatalf("cap %d not a multiple of %d", cap(b), DirectIOAlign)
+	}
+	p.Put(b)
+	if p.Stats().Idle != 1 {
+		t.Fatal("aligned slice should be pooled")
+	}
+
+	if err := p.Reconfigure(Options().SetAlign(512).SetCalibratedSz(4608)); err != nil {
+		t.Fatal(err)
+	}
+	if sz := p.CalibratedSize(); sz%DirectIOAlign != 0 {
+		t.Fatalf("calibrated size %d not a multiple of %d after Reconfigure", sz, DirectIOAlign)
+	}
+}
@@ -2,6 +2,13 @@ package buffer
 
 import "bytes"
 
+// 包级默认池，供 CompressTo/ReadAll/Copy 等便捷函数共用
+var (
+	defaultBufferPool = NewBufferPool()
+	// io.Copy 默认用 32KB，scratch 最大 1MB，再大对拷贝吞吐已无帮助
+	defaultCopyPool = NewBytePool(Options().SetCalibratedSz(32 << 10).SetMaxSize(1 << 20))
+)
+
 // NewBufferPool 创建 *bytes.Buffer 专用池
 func NewBufferPool(opts ...*Option) *Pool[*bytes.Buffer] {
 	return New(
@@ -27,17 +34,41 @@ func NewBufferPool(opts ...*Option) *Pool[*bytes.Buffer] {
 
 // NewBytePool 创建 []byte 专用池
 func NewBytePool(opts ...*Option) *Pool[[]byte] {
+	return newSlicePool[byte](opts...)
+}
+
+// elementDefaults 按元素个数校准的池 (NewSlicePool/NewMapPool) 的默认值
+// 字节池的 MinSize 512/CalibratedSz 1024 放到元素上太大：每次 miss 都会 make 1024 个元素
+func elementDefaults(opts []*Option) []*Option {
+	def := clampDefaults(Options().SetMinSize(8).SetCalibratedSz(64), opts)
+	return append([]*Option{def}, opts...)
+}
+
+// NewSlicePool 创建通用切片池，校准以元素个数为单位 (而非字节)，默认 MinSize 8、CalibratedSz 64
+// 元素含指针时 (如 []*T, [][]byte) 建议 SetClearOnReset(true)，否则池中切片会一直引用旧对象
+func NewSlicePool[E any](opts ...*Option) *Pool[[]E] {
+	return newSlicePool[E](elementDefaults(opts)...)
+}
+
+func newSlicePool[E any](opts ...*Option) *Pool[[]E] {
+	opt := Options().Merge(opts...)
+	clearOnReset := opt.ClearOnReset != nil && *opt.ClearOnReset
+
 	return New(
 		// make: 直接 make slice
-		func(size uint64) []byte {
-			return make([]byte, 0, size)
+		func(size uint64) []E {
+			return make([]E, 0, size)
 		},
 		// reset: 必须 reslice 为 0，并返回新的 slice header
-		func(b []byte) []byte {
+		func(b []E) []E {
+			if clearOnReset {
+				// 按 cap 清理：调用方可能 reslice 过，len 之外也可能残留引用
+				clear(b[:cap(b)])
+			}
 			return b[:0]
 		},
 		// stat: 使用内置 len/cap
-		func(b []byte) (uint64, uint64) {
+		func(b []E) (uint64, uint64) {
 			if b == nil {
 				return 0, 0
 			}
@@ -46,3 +77,42 @@ func NewBytePool(opts ...*Option) *Pool[[]byte] {
 		opts...,
 	)
 }
+
+// NewMapPool 创建 map 专用池，校准以元素个数 (len) 为单位，默认 MinSize 8、CalibratedSz 64
+// map 没有 cap，Put 时以 len 近似容量：装过大量元素的 map clear 后桶不会释放，理应被门卫丢弃
+func NewMapPool[K comparable, V any](opts ...*Option) *Pool[map[K]V] {
+	return New(
+		// make: size 作为容量提示预分配
+		func(size uint64) map[K]V {
+			return make(map[K]V, size)
+		},
+		// reset: clear 保留已分配的桶
+		func(m map[K]V) map[K]V {
+			clear(m)
+			return m
+		},
+		// stat: 空 map 也要能归还，cap 至少为 1
+		func(m map[K]V) (uint64, uint64) {
+			if m == nil {
+				return 0, 0
+			}
+			n := uint64(len(m))
+			return n, max(n, 1)
+		},
+		elementDefaults(opts)...,
+	)
+}
+
+// NewObjectPool 创建无尺寸语义的对象池 (解析器状态、编码器、普通结构体等)
+// 只需要构造和重置函数，保留 AdaptiveRingPool 的自适应容量管理，不做尺寸校准和丢弃判决
+// 注意：不要 Put 零值 (如 nil 指针)，它会被原样放入池中
+func NewObjectPool[T any](newFunc func() T, resetFunc func(T) T, opts ...*Option) *Pool[T] {
+	return New(
+		func(uint64) T {
+			return newFunc()
+		},
+		resetFunc,
+		nil,
+		opts...,
+	)
+}
@@ -0,0 +1,71 @@
+package buffer
+
+import "unsafe"
+
+// Arena 从大块 slab 中切分小 []byte，适合一次请求内大量短命小对象的场景
+// slab 从 []byte 池借用，Reset 时一次性全部归还；slab 大小跟随池的校准值：
+// 归还时 slab 的 len 是实际用量，请求分配得越多，下一轮借到的 slab 越大
+// Arena 不是并发安全的，Reset 之后之前分配的所有切片/字符串都不能再使用
+type Arena struct {
+	pool  *Pool[[]byte]
+	slabs [][]byte // 最后一块是当前 slab，len 为已分配字节数
+}
+
+// NewArena 创建从 pool 借 slab 的 Arena
+func NewArena(pool *Pool[[]byte]) *Arena {
+	return &Arena{pool: pool}
+}
+
+// Alloc 分配 n 字节，内容已清零，len == cap == n (append 不会越界写到相邻分配)
+func (a *Arena) Alloc(n int) []byte {
+	if n <= 0 {
+		return nil
+	}
+	cur := a.current(n)
+	off := len(cur)
+	cur = cur[:off+n]
+	a.slabs[len(a.slabs)-1] = cur
+
+	b := cur[off : off+n : off+n]
+	clear(b) // 池中 slab 只 reslice 过，可能残留旧数据
+	return b
+}
+
+// AllocString 把 b 拷贝进 arena 并以 string 返回，省掉 string(b) 的堆分配
+// 返回的 string 指向 arena 内存，Reset 之后内容会被覆盖，不能跨 Reset 持有
+func (a *Arena) AllocString(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	dst := a.current(len(b))
+	off := len(dst)
+	dst = append(dst, b...)
+	a.slabs[len(a.slabs)-1] = dst
+	return unsafe.String(&dst[off], len(b))
+}
+
+// current 返回剩余空间至少为 n 的当前 slab
+func (a *Arena) current(n int) []byte {
+	if k := len(a.slabs); k > 0 {
+		if cur := a.slabs[k-1]; cap(cur)-len(cur) >= n {
+			return cur
+		}
+	}
+	slab := a.pool.Get()
+	if cap(slab) < n {
+		// 超过 slab 大小的分配单独占一块，归还时按 len 参与校准，后续 slab 会随之变大
+		a.pool.Put(slab)
+		slab = make([]byte, 0, n)
+	}
+	a.slabs = append(a.slabs, slab)
+	return slab
+}
+
+// Reset 把所有 slab 归还给池，arena 可以继续使用
+func (a *Arena) Reset() {
+	for i, slab := range a.slabs {
+		a.pool.Put(slab)
+		a.slabs[i] = nil
+	}
+	a.slabs = a.slabs[:0]
+}
@@ -0,0 +1,72 @@
+package buffer
+
+import "testing"
+
+// TestArenaAlloc 测试分配互不重叠且已清零
+func TestArenaAlloc(t *testing.T) {
+	a := NewArena(NewBytePool(Options().SetMinSize(64).SetCalibratedSz(64)))
+
+	var allocs [][]byte
+	for i := 0; i < 100; i++ {
+		b := a.Alloc(10)
+		if len(b) != 10 || cap(b) != 10 {
+			t.Fatalf("expected len == cap == 10, got %d/%d", len(b), cap(b))
+		}
+		for _, c := range b {
+			if c != 0 {
+				t.Fatal("allocation not zeroed")
+			}
+		}
+		for j := range b {
+			b[j] = byte(i)
+		}
+		allocs = append(allocs, b)
+	}
+	for i, b := range allocs {
+		for _, c := range b {
+			if c != byte(i) {
+				t.Fatalf("allocation %d overwritten", i)
+			}
+		}
+	}
+	a.Reset()
+
+	// Reset 后复用的 slab 也必须清零
+	for _, c := range a.Alloc(64) {
+		if c != 0 {
+			t.Fatal("reused slab not zeroed")
+		}
+	}
+	a.Reset()
+}
+
+// TestArenaAllocString 测试字符串分配
+func TestArenaAllocString(t *testing.T) {
+	a := NewArena(NewBytePool())
+	src := []byte("hello")
+	s := a.AllocString(src)
+	src[0] = 'j'
+	if s != "hello" {
+		t.Fatalf("expected copy, got %q", s)
+	}
+	if a.AllocString(nil) != "" {
+		t.Fatal("expected empty string")
+	}
+	a.Reset()
+}
+
+// TestArenaLargeAlloc 测试超过 slab 的分配推动 slab 校准变大
+func TestArenaLargeAlloc(t *testing.T) {
+	pool := NewBytePool(Options().SetMinSize(512).SetCalibratedSz(512).SetCalibratePeriod(10))
+	a := NewArena(pool)
+
+	for i := 0; i < 50; i++ {
+		if b := a.Alloc(4096); len(b) != 4096 {
+			t.Fatalf("unexpected len %d", len(b))
+		}
+		a.Reset()
+	}
+	if sz := pool.CalibratedSize(); sz <= 512 {
+		t.Errorf("expected slab size to grow, got %d", sz)
+	}
+}
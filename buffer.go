@@ -6,12 +6,12 @@ import (
 )
 
 const (
-	// emaUpFactor: 上涨时的平滑因子 (0~1)。
+	// emaUpFactor: 上涨时的平滑因子 (0~1)，默认值，可用 SetEmaUpFactor 覆盖。
 	// 值越小，对新值越敏感（涨得越快）。0.4 代表保留 40% 历史，接纳 60% 新值。
 	// 选 0.4 而不是 0 是为了过滤掉偶发的“超级尖峰”。
 	emaUpFactor = 0.4
 
-	// emaDownFactor: 下跌时的平滑因子 (0~1)。
+	// emaDownFactor: 下跌时的平滑因子 (0~1)，默认值，可用 SetEmaDownFactor 覆盖。
 	// 值越大，对历史越执着（跌得越慢）。0.9 代表保留 90% 历史，只接纳 10% 下跌。
 	// 这有助于在流量波动时保持水位，避免频繁扩容。
 	emaDownFactor = 0.8
@@ -34,58 +34,179 @@ type Pool[T any] struct {
 	// 这些函数消除了 *bytes.Buffer 和 []byte 的差异
 	// 虽然是函数指针调用，但在现代 CPU 上开销极低
 	makeFunc  func(size uint64) T
-	resetFunc func(T) T // 返回 T 是为了兼容 slice 的 reslice 操作
-	statFunc  func(T) (used, cap uint64)
+	resetFunc func(T) T                  // 返回 T 是为了兼容 slice 的 reslice 操作
+	statFunc  func(T) (used, cap uint64) // 为 nil 时是对象池模式，跳过校准和丢弃判决
+	dropFunc  func(T)                    // 被丢弃对象的回调，nil 表示交给 GC (mmap 等非堆内存需要手动释放)
 
-	// 1. 配置参数 (只读，无需原子操作)
-	minSize         uint64
-	maxSize         uint64
-	calibratePeriod uint64
-	maxPercent      float64
+	leakCheck bool          // Acquire 的 Handle 是否挂 finalizer
+	leaks     atomic.Uint64 // finalizer 发现泄漏的 Handle 数
+
+	calibrator
+}
+
+// calibrator 尺寸校准状态，Pool 和 PoolFor 共用
+type calibrator struct {
+	// 1. 配置参数 (读多写少，整体原子替换，支持 Reconfigure)
+	cfg atomic.Pointer[calibConfig]
 
 	_ padding // 隔离只读区和读写区
 
 	// 2. 运行时状态 (高频读写，原子操作)
-	calls        uint64
-	_            padding // 隔离 calls 和 maxUsage
-	maxUsage     uint64  //校准区间的最大使用者,是多少
-	_            padding // 隔离 maxUsage 和 calibratedSz
-	calibratedSz uint64  //校准值，最新分配的大小
+	// 用 atomic.Uint64 而不是裸 uint64：前面的 cfg 在 32 位平台上只占 4 字节，
+	// 裸 uint64 会落在非 8 字节对齐的偏移上，64 位原子操作直接 panic
+	calls        atomic.Uint64
+	_            padding       // 隔离 calls 和 maxUsage
+	maxUsage     atomic.Uint64 //校准区间的最大使用者,是多少
+	_            padding       // 隔离 maxUsage 和 calibratedSz
+	calibratedSz atomic.Uint64 //校准值，最新分配的大小
 }
 
-// New 创建一个新的智能池
-func New[T any](makeFunc func(uint64) T, resetFunc func(T) T, statFunc func(T) (uint64, uint64), opts ...*Option) *Pool[T] {
-	opt := Options().
+// calibConfig 校准配置，创建后不再修改，Reconfigure 时整体替换
+type calibConfig struct {
+	minSize         uint64
+	maxSize         uint64
+	calibratePeriod uint64
+	maxPercent      float64
+	align           uint64 // 校准值向上取整的块大小，<= 1 表示不取整
+	emaUp           float64
+	emaDown         float64
+}
+
+// defaultOptions 合并默认配置和用户配置
+func defaultOptions(opts ...*Option) Option {
+	def := Options().
 		SetMinSize(512).          // 最小不小于 512B
 		SetMaxSize(64 << 20).     // 最大不超过 64MB (防止 OOM) 64<< 10 是64k
 		SetCalibratePeriod(1000). //多久校准一次
 		SetMaxPercent(1.5).
 		SetCalibratedSz(1024). //校准就是修改这个size,最新适合的size
-		Merge(opts...)
-	p := &Pool[T]{
-		pool:            NewAdaptiveRingPool[T](nil),
+		SetEmaUpFactor(emaUpFactor).
+		SetEmaDownFactor(emaDownFactor)
+	return clampDefaults(def, opts).Merge(opts...)
+}
+
+// clampDefaults 用户设置了更小的 MaxSize 时，把默认的 MinSize/CalibratedSz 截断到它
+// 默认值只是起点，不截断的话 normalize 会把 MaxSize 抬回 MinSize，用户的上限被悄悄放大；
+// 默认 MaxSize 是防 OOM 的上限，不做调整
+func clampDefaults(def *Option, opts []*Option) *Option {
+	user := Options().Merge(opts...)
+	if user.MaxSize == nil {
+		return def
+	}
+	limit := max(*user.MaxSize, 1) // MaxSize 为 0 会被 normalize 修正为 1
+	if def.MinSize != nil {
+		def.SetMinSize(min(*def.MinSize, limit))
+	}
+	if def.CalibratedSz != nil {
+		def.SetCalibratedSz(min(*def.CalibratedSz, limit))
+	}
+	return def
+}
+
+func newCalibConfig(opt Option) *calibConfig {
+	cfg := &calibConfig{
 		minSize:         *opt.MinSize,
 		maxSize:         *opt.MaxSize,
 		calibratePeriod: *opt.CalibratePeriod,
 		maxPercent:      *opt.MaxPercent,
-		calibratedSz:    *opt.CalibratedSz, // 初始猜测值
-		makeFunc:        makeFunc,
-		resetFunc:       resetFunc,
-		statFunc:        statFunc,
+		emaUp:           *opt.EmaUpFactor,
+		emaDown:         *opt.EmaDownFactor,
+	}
+	if opt.Align != nil {
+		cfg.align = *opt.Align
+	}
+	return cfg
+}
+
+// options 把配置还原成 Option，便于在当前配置上合并增量
+func (cfg *calibConfig) options() *Option {
+	return Options().
+		SetMinSize(cfg.minSize).
+		SetMaxSize(cfg.maxSize).
+		SetCalibratePeriod(cfg.calibratePeriod).
+		SetMaxPercent(cfg.maxPercent).
+		SetAlign(cfg.align).
+		SetEmaUpFactor(cfg.emaUp).
+		SetEmaDownFactor(cfg.emaDown)
+}
+
+// clamp 按 [minSize, maxSize] 截断并对齐
+func (cfg *calibConfig) clamp(sz uint64) uint64 {
+	sz = max(cfg.minSize, sz)
+	sz = min(sz, cfg.maxSize)
+	// 按块对齐时向上取整 (MaxSize 应当是 align 的整数倍，否则取整后会略超 MaxSize)
+	return cfg.alignUp(sz)
+}
+
+// alignUp 按 align 向上取整
+func (cfg *calibConfig) alignUp(sz uint64) uint64 {
+	if cfg.align <= 1 {
+		return sz
 	}
+	return (sz + cfg.align - 1) / cfg.align * cfg.align
+}
+
+// newRingPool 按 RingMinCap/RingMaxCap/Overflow 创建环形池，New 函数由调用方设置
+func newRingPool[T any](opt Option) *AdaptiveRingPool[T] {
+	minCap, maxCap := DefaultMinCapacity, DefaultMaxCapacity
+	if opt.RingMinCap != nil {
+		minCap = *opt.RingMinCap
+	}
+	if opt.RingMaxCap != nil {
+		maxCap = *opt.RingMaxCap
+	}
+	p := NewAdaptiveRingPoolWithLimit[T](minCap, maxCap, nil)
+	p.Overflow = opt.Overflow != nil && *opt.Overflow
+	return p
+}
 
+func (c *calibrator) init(opt Option) {
+	opt.normalize()
+	cfg := newCalibConfig(opt)
+	c.cfg.Store(cfg)
 	// 确保初始值合法
-	p.calibratedSz = max(p.minSize, p.calibratedSz)
+	c.calibratedSz.Store(cfg.alignUp(max(cfg.minSize, *opt.CalibratedSz)))
+}
+
+// New 创建一个新的智能池
+// 非法配置会被静默修正为最接近的合法值 (规则见 Option.normalize)，需要报错请使用 NewE
+func New[T any](makeFunc func(uint64) T, resetFunc func(T) T, statFunc func(T) (uint64, uint64), opts ...*Option) *Pool[T] {
+	opt := defaultOptions(opts...)
+	p := &Pool[T]{
+		pool:      newRingPool[T](opt),
+		makeFunc:  makeFunc,
+		resetFunc: resetFunc,
+		statFunc:  statFunc,
+		leakCheck: opt.LeakCheck != nil && *opt.LeakCheck,
+	}
+	p.calibrator.init(opt)
 
 	p.pool.New = func() T {
 		// 原子读取当前的校准大小
-		size := atomic.LoadUint64(&p.calibratedSz)
+		size := p.calibratedSz.Load()
 		return p.makeFunc(size)
 	}
+	if statFunc != nil {
+		// 溢出层中的对象要按当前门卫重新判决 (Reconfigure 或内存压力可能已经收紧)
+		p.pool.admit = func(v T) bool {
+			_, capVal := p.statFunc(v)
+			return p.admits(capVal)
+		}
+	}
+
+	if opt.Snapshot != nil {
+		p.Restore(*opt.Snapshot)
+	}
 
 	return p
 }
 
+// setDropFunc 设置丢弃回调，门卫丢弃和环形池丢弃都会触发
+func (p *Pool[T]) setDropFunc(f func(T)) {
+	p.dropFunc = f
+	p.pool.OnDrop = f
+}
+
 // Get 获取原生 *bytes.Buffer (零分配)
 func (p *Pool[T]) Get() T {
 	// 类型断言在 Go 中非常快
@@ -94,29 +215,68 @@ func (p *Pool[T]) Get() T {
 
 // Put 归还并智能处理
 func (p *Pool[T]) Put(b T) {
-	// if b == nil {
-	// 	return
-	// }
+	if debug {
+		checkScopedPut(b)
+	}
+
+	// 对象池模式：没有尺寸语义，只复用环形池的容量管理
+	if p.statFunc == nil {
+		p.pool.Put(p.resetFunc(b))
+		return
+	}
 
 	// 此时 buffer 已包含数据，Len 是实际使用量，Cap 是底层数组容量
-	// used := uint64(b.Len())
-	// capVal := uint64(b.Cap())
 	used, capVal := p.statFunc(b) // ==nil cap 返回0
-	if capVal == 0 {
+	p.put(b, used, capVal)
+}
+
+// put 按给定用量归还，用于调用方比 statFunc 更清楚实际需求的场景 (如 Copy 的 scratch)
+func (p *Pool[T]) put(b T, used, capVal uint64) {
+	if !p.observe(used, capVal) {
+		if p.dropFunc != nil {
+			p.dropFunc(b)
+		}
 		return
 	}
 
+	// 必须 Reset 才能复用，且要用返回值 (slice reslice 后 header 变了)
+	p.pool.Put(p.resetFunc(b))
+}
+
+// Drain 释放池中所有空闲对象 (会触发丢弃回调)，返回释放的数量，之后池仍可继续使用
+// mmap 等堆外内存池不再使用时必须调用，否则空闲的映射永远不会被归还给操作系统
+func (p *Pool[T]) Drain() int {
+	return p.pool.Drain()
+}
+
+// CalibratedSize 返回当前校准值，即新建对象时使用的尺寸
+func (c *calibrator) CalibratedSize() uint64 {
+	return c.calibratedSz.Load()
+}
+
+// admits 容量为 capVal 的对象按当前门卫是否可以复用
+func (c *calibrator) admits(capVal uint64) bool {
+	return capVal <= uint64(float64(c.CalibratedSize())*c.cfg.Load().maxPercent)
+}
+
+// observe 记录一次归还的用量，按需触发校准，返回是否值得放回池中
+func (c *calibrator) observe(used, capVal uint64) bool {
+	if capVal == 0 {
+		return false
+	}
+
 	// 1. 智能采样更新 maxUsage (性能优化核心)
 	// 不要每次 Put 都去 CAS 抢锁。
 	// 策略：如果流量突增(used > current)，必须记录；否则低概率采样记录。
-	currentSz := atomic.LoadUint64(&p.calibratedSz)
+	cfg := c.cfg.Load()
+	currentSz := c.calibratedSz.Load()
 	shouldRecord := false
-	newCalls := atomic.AddUint64(&p.calls, 1)
+	newCalls := c.calls.Add(1)
 
 	if used > currentSz {
 		// 流量突增，必须记录，防止下一轮分配过小
 		shouldRecord = true
-	} else if used > p.minSize {
+	} else if used > cfg.minSize {
 		// 只有大于最小值的包才有记录意义。
 		// 这里使用简单的位运算做低成本采样 (每 16 次记录一次)
 		// 注意：这里用 b.Cap() 的地址或者其他随机数做判断源均可，
@@ -131,45 +291,40 @@ func (p *Pool[T]) Put(b T) {
 
 	if shouldRecord {
 		for {
-			oldMax := atomic.LoadUint64(&p.maxUsage)
+			oldMax := c.maxUsage.Load()
 			if used <= oldMax {
 				break
 			}
 			// CAS 乐观锁更新
-			if atomic.CompareAndSwapUint64(&p.maxUsage, oldMax, used) {
+			if c.maxUsage.CompareAndSwap(oldMax, used) {
 				break
 			}
 		}
 	}
 
 	// 2. 触发校准 (原子计数器)
-	if newCalls >= p.calibratePeriod {
+	if newCalls >= cfg.calibratePeriod {
 		// 只有获得重置权的那个 goroutine 去执行 calibrate
-		if atomic.CompareAndSwapUint64(&p.calls, newCalls, 0) {
-			p.calibrate()
-			currentSz = atomic.LoadUint64(&p.calibratedSz)
+		if c.calls.CompareAndSwap(newCalls, 0) {
+			c.calibrate()
+			currentSz = c.calibratedSz.Load()
 		}
 	}
 
 	// 3. 智能丢弃判决
 	// 如果当前 buffer 容量远超当前需要的尺寸，归还给 pool 会导致内存泄漏（虚高）。
 	// 直接丢弃，让 GC 回收。
-	if capVal > uint64(float64(currentSz)*p.maxPercent) {
-		return
-	}
-
-	// 必须 Reset 才能复用
-	// b.Reset()
-	p.resetFunc(b)
-	p.pool.Put(b)
+	return capVal <= uint64(float64(currentSz)*cfg.maxPercent)
 }
 
 // calibrate 计算周期内新的基准大小 (核心算法)
 // 此方法在单独的 goroutine 或低频路径执行，不需要极度优化，重在算法逻辑
-func (p *Pool[T]) calibrate() {
+func (c *calibrator) calibrate() {
+	cfg := c.cfg.Load()
+
 	// 1. 获取并重置本周期的最大使用量
-	newMax := atomic.LoadUint64(&p.maxUsage)
-	atomic.StoreUint64(&p.maxUsage, 0)
+	newMax := c.maxUsage.Load()
+	c.maxUsage.Store(0)
 
 	// 2. 只有当本周期有有效数据时才调整
 	if newMax == 0 {
@@ -178,23 +333,23 @@ func (p *Pool[T]) calibrate() {
 	}
 
 	// 3. 限制范围 (Bounds Check)
-	newMax = max(p.minSize, newMax)
-	newMax = min(newMax, p.maxSize)
+	newMax = max(cfg.minSize, newMax)
+	newMax = min(newMax, cfg.maxSize)
 
 	// 4. 读取旧的校准值
-	oldSz := atomic.LoadUint64(&p.calibratedSz)
+	oldSz := c.calibratedSz.Load()
 
 	// 5. EMA (指数加权移动平均) 算法 - 快涨慢跌
 	var nextSz uint64
 
 	if newMax > oldSz {
-		// 【上涨】：使用较小的因子 (emaUpFactor)，让权重更多向 newMax 倾斜
+		// 【上涨】：使用较小的因子 (默认 emaUpFactor)，让权重更多向 newMax 倾斜
 		// 目的：快速响应流量增长，减少 Get 后的 Grow() 开销
-		nextSz = uint64(float64(oldSz)*emaUpFactor + float64(newMax)*(1-emaUpFactor))
+		nextSz = uint64(float64(oldSz)*cfg.emaUp + float64(newMax)*(1-cfg.emaUp))
 	} else {
-		// 【下跌】：使用较大的因子 (emaDownFactor)，让权重主要保留在 oldSz
+		// 【下跌】：使用较大的因子 (默认 emaDownFactor)，让权重主要保留在 oldSz
 		// 目的：抵抗抖动，只有流量长期低迷时才缓慢缩容
-		nextSz = uint64(float64(oldSz)*emaDownFactor + float64(newMax)*(1-emaDownFactor))
+		nextSz = uint64(float64(oldSz)*cfg.emaDown + float64(newMax)*(1-cfg.emaDown))
 	}
 
 	// 6. 微量溢价 (Buffer Premium)
@@ -205,9 +360,8 @@ func (p *Pool[T]) calibrate() {
 	}
 
 	// 再次限制最大值（防止溢价后越界）
-	nextSz = max(p.minSize, nextSz)
-	nextSz = min(nextSz, p.maxSize)
+	nextSz = cfg.clamp(nextSz)
 
 	// 7. 原子更新最终值
-	atomic.StoreUint64(&p.calibratedSz, nextSz)
+	c.calibratedSz.Store(nextSz)
 }
@@ -101,6 +101,61 @@ func BenchmarkDirectMakeByteSlice(b *testing.B) {
 	}
 }
 
+// =============================================================================
+// 基准测试 - Poolable 方法调用 vs 函数指针
+// =============================================================================
+
+// poolableBuffer 实现 Poolable，Reset/Len/Cap 直接来自 bytes.Buffer
+type poolableBuffer struct {
+	bytes.Buffer
+}
+
+func (*poolableBuffer) Make(size uint64) *poolableBuffer {
+	b := &poolableBuffer{}
+	b.Grow(int(size))
+	return b
+}
+
+// BenchmarkPoolForGetPut NewFor：类型参数的方法调用 (指针类型经字典间接调用，与下面的函数指针版本持平)
+func BenchmarkPoolForGetPut(b *testing.B) {
+	p := NewFor[*poolableBuffer]()
+	data := make([]byte, 1024)
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf := p.Get()
+		buf.Write(data)
+		p.Put(buf)
+	}
+}
+
+// BenchmarkPoolFuncGetPut 对比：同一类型走 New 的函数指针路径
+func BenchmarkPoolFuncGetPut(b *testing.B) {
+	p := New(
+		(*poolableBuffer)(nil).Make,
+		func(buf *poolableBuffer) *poolableBuffer {
+			buf.Reset()
+			return buf
+		},
+		func(buf *poolableBuffer) (uint64, uint64) {
+			if buf == nil {
+				return 0, 0
+			}
+			return uint64(buf.Len()), uint64(buf.Cap())
+		},
+	)
+	data := make([]byte, 1024)
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf := p.Get()
+		buf.Write(data)
+		p.Put(buf)
+	}
+}
+
 // =============================================================================
 // 并发基准测试 - 多 goroutine 吞吐量
 // =============================================================================
@@ -494,6 +494,158 @@ func TestBytePoolConcurrency(t *testing.T) {
 	t.Log("Byte pool concurrent test completed")
 }
 
+// =============================================================================
+// 通用切片池 / map 池测试
+// =============================================================================
+
+// TestSlicePoolClearOnReset 测试 ClearOnReset 会清理元素引用
+func TestSlicePoolClearOnReset(t *testing.T) {
+	p := NewSlicePool[*int](Options().SetClearOnReset(true).SetMinSize(4).SetCalibratedSz(8))
+
+	s := p.Get()
+	v := 42
+	s = append(s, &v, &v)
+	p.Put(s[:1]) // 故意 reslice，len 之外的元素也应被清理
+
+	s2 := p.Get()
+	for i, e := range s2[:cap(s2)] {
+		if e != nil {
+			t.Fatalf("element %d not cleared", i)
+		}
+	}
+	p.Put(s2)
+}
+
+// TestSlicePoolElementCount 测试切片池按元素个数分配
+func TestSlicePoolElementCount(t *testing.T) {
+	p := NewSlicePool[int64](Options().SetMinSize(16).SetCalibratedSz(32))
+
+	s := p.Get()
+	if cap(s) != 32 {
+		t.Fatalf("expected cap 32 elements, got %d", cap(s))
+	}
+	p.Put(s)
+}
+
+// TestMapPool 测试 map 池复用与清理
+func TestMapPool(t *testing.T) {
+	p := NewMapPool[string, int](Options().SetMinSize(8).SetCalibratedSz(16))
+
+	m := p.Get()
+	m["a"] = 1
+	p.Put(m)
+
+	m2 := p.Get()
+	if len(m2) != 0 {
+		t.Fatalf("expected empty map, got %v", m2)
+	}
+	p.Put(m2) // 空 map 也应能归还
+
+	p.Put(nil) // 应该安全
+}
+
+// TestElementPoolDefaults 测试按元素个数校准的池使用元素默认值，字节池不受影响
+func TestElementPoolDefaults(t *testing.T) {
+	type event struct{ a, b, c int64 }
+	if sz := NewSlicePool[event]().CalibratedSize(); sz != 64 {
+		t.Fatalf("expected slice pool calibrated size 64, got %d", sz)
+	}
+	if sz := NewMapPool[string, int]().CalibratedSize(); sz != 64 {
+		t.Fatalf("expected map pool calibrated size 64, got %d", sz)
+	}
+	if cfg := NewSlicePool[event]().cfg.Load(); cfg.minSize != 8 {
+		t.Fatalf("expected slice pool min size 8, got %d", cfg.minSize)
+	}
+	if sz := NewSlicePool[event](Options().SetCalibratedSz(256)).CalibratedSize(); sz != 256 {
+		t.Fatalf("expected user calibrated size 256, got %d", sz)
+	}
+	if sz := NewBytePool().CalibratedSize(); sz != 1024 {
+		t.Fatalf("expected byte pool calibrated size 1024, got %d", sz)
+	}
+}
+
+// TestBytePoolResetLen 测试归还后再取出的切片 len 为 0
+func TestBytePoolResetLen(t *testing.T) {
+	p := NewBytePool()
+
+	b := p.Get()
+	b = append(b, "hello"...)
+	p.Put(b)
+
+	b2 := p.Get()
+	if len(b2) != 0 {
+		t.Fatalf("expected len 0 after reset, got %d", len(b2))
+	}
+	p.Put(b2)
+}
+
+// =============================================================================
+// 对象池测试
+// =============================================================================
+
+type testParser struct {
+	depth int
+	stack []string
+}
+
+// TestObjectPool 测试无尺寸语义的对象池
+func TestObjectPool(t *testing.T) {
+	created := 0
+	p := NewObjectPool(
+		func() *testParser {
+			created++
+			return &testParser{}
+		},
+		func(ps *testParser) *testParser {
+			ps.depth = 0
+			ps.stack = ps.stack[:0]
+			return ps
+		},
+	)
+
+	ps := p.Get()
+	ps.depth = 3
+	ps.stack = append(ps.stack, "a")
+	p.Put(ps)
+
+	ps2 := p.Get()
+	if ps2 != ps {
+		t.Fatal("expected object to be reused")
+	}
+	if ps2.depth != 0 || len(ps2.stack) != 0 {
+		t.Fatalf("object not reset: %+v", ps2)
+	}
+	p.Put(ps2)
+
+	if created != 1 {
+		t.Fatalf("expected 1 allocation, got %d", created)
+	}
+}
+
+// TestPoolForCalibration 测试 NewFor 与 Pool 共用同一套校准逻辑
+func TestPoolForCalibration(t *testing.T) {
+	p := NewFor[*poolableBuffer](Options().SetCalibratePeriod(100).SetCalibratedSz(512))
+
+	buf := p.Get()
+	initialCap := buf.Cap()
+	p.Put(buf)
+
+	for i := 0; i < 200; i++ {
+		buf := p.Get()
+		buf.Write(make([]byte, 4096))
+		p.Put(buf)
+	}
+
+	buf = p.Get()
+	if buf.Len() != 0 {
+		t.Fatalf("expected reset buffer, got len %d", buf.Len())
+	}
+	if buf.Cap() <= initialCap {
+		t.Errorf("expected growth, but cap stayed at %d", buf.Cap())
+	}
+	p.Put(buf)
+}
+
 // =============================================================================
 // 内存泄漏检测
 // =============================================================================
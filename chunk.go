@@ -0,0 +1,173 @@
+package buffer
+
+import (
+	"io"
+	"net"
+)
+
+// NewBlockPool 创建固定尺寸的 []byte 块池，供 ChunkBuffer 使用
+// MinSize = MaxSize = CalibratedSz = blockSize，校准永远停在 blockSize 上
+// blockSize 为 0 时 panic：0 容量的块会让 Write/ReadFrom 永远写不进数据
+func NewBlockPool(blockSize uint64, opts ...*Option) *Pool[[]byte] {
+	if blockSize == 0 {
+		panic("buffer: block size must be > 0")
+	}
+	return NewBytePool(append([]*Option{
+		Options().
+			SetMinSize(blockSize).
+			SetMaxSize(blockSize).
+			SetCalibratedSz(blockSize),
+	}, opts...)...)
+}
+
+// ChunkBuffer 由池化块拼接而成的分段缓冲区
+// 与 bytes.Buffer 不同，它不需要连续内存，写入大数据时不会触发整体扩容拷贝，
+// 也不会产生一个大到被 maxPercent 门卫直接丢弃的 buffer
+type ChunkBuffer struct {
+	pool   *Pool[[]byte]
+	blocks [][]byte    // 每块 len 为已写入字节数，写满 cap 后再借新块
+	off    int         // blocks[0] 中已读取的字节数
+	vec    net.Buffers // WriteTo 复用的向量，避免每次分配
+}
+
+// NewChunkBuffer 创建从 pool 借块的分段缓冲区，pool 一般由 NewBlockPool 创建
+func NewChunkBuffer(pool *Pool[[]byte]) *ChunkBuffer {
+	return &ChunkBuffer{pool: pool}
+}
+
+// Len 返回未读取的字节数
+func (c *ChunkBuffer) Len() int {
+	n := -c.off
+	for _, b := range c.blocks {
+		n += len(b)
+	}
+	return n
+}
+
+// tail 返回还有剩余空间的最后一块，没有则从池中借一块
+func (c *ChunkBuffer) tail() []byte {
+	if n := len(c.blocks); n > 0 {
+		if b := c.blocks[n-1]; len(b) < cap(b) {
+			return b
+		}
+	}
+	b := c.pool.Get()
+	c.blocks = append(c.blocks, b)
+	return b
+}
+
+// Write 实现 io.Writer，总是写完 p
+func (c *ChunkBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		b := c.tail()
+		m := copy(b[len(b):cap(b)], p)
+		c.blocks[len(c.blocks)-1] = b[:len(b)+m]
+		p = p[m:]
+	}
+	return n, nil
+}
+
+// WriteString 实现 io.StringWriter
+func (c *ChunkBuffer) WriteString(s string) (int, error) {
+	n := len(s)
+	for len(s) > 0 {
+		b := c.tail()
+		m := copy(b[len(b):cap(b)], s)
+		c.blocks[len(c.blocks)-1] = b[:len(b)+m]
+		s = s[m:]
+	}
+	return n, nil
+}
+
+// ReadFrom 实现 io.ReaderFrom，直接读进块的空闲区域，没有中间拷贝
+func (c *ChunkBuffer) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	for {
+		b := c.tail()
+		m, err := r.Read(b[len(b):cap(b)])
+		if m < 0 {
+			panic("buffer: reader returned negative count from Read")
+		}
+		c.blocks[len(c.blocks)-1] = b[:len(b)+m]
+		total += int64(m)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// Read 实现 io.Reader，读完的块立即归还给池
+func (c *ChunkBuffer) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if c.Len() == 0 {
+		c.Reset()
+		return 0, io.EOF
+	}
+	n := 0
+	for n < len(p) && len(c.blocks) > 0 && c.off < len(c.blocks[0]) {
+		m := copy(p[n:], c.blocks[0][c.off:])
+		n += m
+		c.consume(m)
+	}
+	return n, nil
+}
+
+// WriteTo 实现 io.WriterTo，使用 net.Buffers 写出
+// w 是 *net.TCPConn 等连接时会走 writev，一次系统调用写出所有块
+func (c *ChunkBuffer) WriteTo(w io.Writer) (int64, error) {
+	if len(c.blocks) == 0 {
+		return 0, nil
+	}
+	c.vec = append(c.vec[:0], c.blocks[0][c.off:])
+	c.vec = append(c.vec, c.blocks[1:]...)
+	vec := c.vec // WriteTo 会消费 vec 本身，保留 c.vec 的底层数组以便复用
+	n, err := vec.WriteTo(w)
+	clear(c.vec) // 断开对块的引用
+	c.consume(int(n))
+	return n, err
+}
+
+// consume 丢弃前 n 个未读字节，读完的块归还给池
+func (c *ChunkBuffer) consume(n int) {
+	for n > 0 && len(c.blocks) > 0 {
+		b := c.blocks[0]
+		left := len(b) - c.off
+		if n < left {
+			c.off += n
+			return
+		}
+		n -= left
+		c.off = 0
+		// 最后一块还可能继续写入，读完了也不用归还，Reset 即可
+		if len(c.blocks) == 1 && len(b) < cap(b) {
+			c.blocks[0] = b[:0]
+			return
+		}
+		c.pool.Put(b)
+		c.blocks[0] = nil
+		c.blocks = c.blocks[1:]
+	}
+}
+
+// Reset 清空并把所有块归还给池，之后可以继续使用
+func (c *ChunkBuffer) Reset() {
+	for i, b := range c.blocks {
+		c.pool.Put(b)
+		c.blocks[i] = nil
+	}
+	c.blocks = c.blocks[:0]
+	c.off = 0
+}
+
+// Release 归还所有块并释放内部切片，之后不应再使用
+func (c *ChunkBuffer) Release() {
+	c.Reset()
+	c.blocks = nil
+	c.vec = nil
+}
@@ -0,0 +1,130 @@
+package buffer
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// TestChunkBufferWriteRead 测试跨块写入和读取
+func TestChunkBufferWriteRead(t *testing.T) {
+	c := NewChunkBuffer(NewBlockPool(16))
+	src := strings.Repeat("0123456789", 10) // 100 字节，跨 7 块
+
+	c.WriteString(src[:50])
+	c.Write([]byte(src[50:]))
+	if c.Len() != len(src) {
+		t.Fatalf("expected len %d, got %d", len(src), c.Len())
+	}
+
+	out, err := io.ReadAll(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != src {
+		t.Fatalf("round trip mismatch: %q", out)
+	}
+	if c.Len() != 0 || len(c.blocks) != 0 {
+		t.Fatalf("expected empty buffer, got len %d blocks %d", c.Len(), len(c.blocks))
+	}
+}
+
+// TestChunkBufferReadFromWriteTo 测试 ReadFrom/WriteTo
+func TestChunkBufferReadFromWriteTo(t *testing.T) {
+	c := NewChunkBuffer(NewBlockPool(64))
+	src := bytes.Repeat([]byte("abc"), 1000)
+
+	n, err := c.ReadFrom(bytes.NewReader(src))
+	if err != nil || n != int64(len(src)) {
+		t.Fatalf("ReadFrom: n=%d err=%v", n, err)
+	}
+
+	// 先读掉一部分，验证 WriteTo 从读偏移开始
+	head := make([]byte, 10)
+	c.Read(head)
+
+	var dst bytes.Buffer
+	n, err = c.WriteTo(&dst)
+	if err != nil || n != int64(len(src)-10) {
+		t.Fatalf("WriteTo: n=%d err=%v", n, err)
+	}
+	if !bytes.Equal(append(head, dst.Bytes()...), src) {
+		t.Fatal("content mismatch")
+	}
+	if c.Len() != 0 {
+		t.Fatalf("expected empty after WriteTo, got %d", c.Len())
+	}
+}
+
+// TestChunkBufferWriteToConn 测试对 net.Conn 的向量写
+func TestChunkBufferWriteToConn(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+
+	src := bytes.Repeat([]byte("x"), 10000)
+	done := make(chan []byte)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			done <- nil
+			return
+		}
+		defer conn.Close()
+		b, _ := io.ReadAll(conn)
+		done <- b
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewChunkBuffer(NewBlockPool(1024))
+	c.Write(src)
+	if _, err := c.WriteTo(conn); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	if got := <-done; !bytes.Equal(got, src) {
+		t.Fatalf("received %d bytes, want %d", len(got), len(src))
+	}
+}
+
+// TestChunkBufferReset 测试 Reset 后块归还给池并被复用
+func TestChunkBufferReset(t *testing.T) {
+	pool := NewBlockPool(32)
+	c := NewChunkBuffer(pool)
+
+	c.Write(make([]byte, 100)) // 4 块
+	c.Reset()
+	if c.Len() != 0 {
+		t.Fatal("expected empty after Reset")
+	}
+	if idle := pool.Stats().Idle; idle != 4 {
+		t.Fatalf("expected 4 blocks returned, idle %d", idle)
+	}
+
+	c.Write([]byte("y"))
+	if idle := pool.Stats().Idle; idle != 3 {
+		t.Fatalf("expected a returned block to be reused, idle %d", idle)
+	}
+	c.Release()
+	if idle := pool.Stats().Idle; idle != 4 {
+		t.Fatalf("expected all blocks back after Release, idle %d", idle)
+	}
+}
+
+// TestBlockPoolZeroSize 测试 blockSize 为 0 时 panic，而不是让 Write 死循环
+func TestBlockPoolZeroSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for zero block size")
+		}
+	}()
+	NewBlockPool(0)
+}
@@ -0,0 +1,265 @@
+package buffer
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// 压缩等级范围：HuffmanOnly(-2) ~ BestCompression(9)，gzip/flate/zlib 三者一致
+const (
+	minLevel   = flate.HuffmanOnly
+	maxLevel   = flate.BestCompression
+	levelCount = maxLevel - minLevel + 1
+)
+
+// zlibEmptyStream 一个合法的空 zlib 流 (header + 空的最终块 + adler32)
+// zlib 的 reader 类型未导出，只能通过 NewReader 构造，而 NewReader 会立即读取 header
+var zlibEmptyStream = []byte{0x78, 0x9c, 0x03, 0x00, 0x00, 0x00, 0x00, 0x01}
+
+// eofReader 同时实现 io.Reader 和 io.ByteReader (即 flate.Reader)
+// 归还 reader 时用它 Reset，断开对调用方数据源的引用，且不会产生 bufio 分配
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
+func (eofReader) ReadByte() (byte, error)  { return 0, io.EOF }
+
+// writeResetter gzip/flate/zlib 三种 Writer 的公共方法集
+type writeResetter interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// WriterPool 按压缩等级分桶的压缩器池
+// 不同等级的 Writer 内部状态不同，不能混用，所以每个等级一个独立的 Pool
+type WriterPool[T writeResetter] struct {
+	pools [levelCount]*Pool[*LevelWriter[T]]
+}
+
+// LevelWriter WriterPool 借出的压缩器，创建时记下压缩等级，Put 按它放回对应的桶，
+// 调用方不需要 (也没有机会) 再传一次等级
+type LevelWriter[T writeResetter] struct {
+	w     T
+	level int
+}
+
+// Write 实现 io.Writer
+func (lw *LevelWriter[T]) Write(p []byte) (int, error) {
+	return lw.w.Write(p)
+}
+
+// Flush 刷出已压缩的数据，不结束压缩流
+func (lw *LevelWriter[T]) Flush() error {
+	return lw.w.Flush()
+}
+
+// Close 结束压缩流并刷出尾部数据，不会关闭下层 io.Writer
+func (lw *LevelWriter[T]) Close() error {
+	return lw.w.Close()
+}
+
+// Writer 返回底层压缩器，用于设置 gzip.Header 等特有字段
+func (lw *LevelWriter[T]) Writer() T {
+	return lw.w
+}
+
+// Level 返回压缩等级
+func (lw *LevelWriter[T]) Level() int {
+	return lw.level
+}
+
+func newWriterPool[T writeResetter](newFunc func(level int) T, opts ...*Option) *WriterPool[T] {
+	p := &WriterPool[T]{}
+	for i := range p.pools {
+		level := i + minLevel
+		p.pools[i] = NewObjectPool(
+			// make: 按等级创建
+			func() *LevelWriter[T] {
+				return &LevelWriter[T]{w: newFunc(level), level: level}
+			},
+			// reset: 断开对上一个 dst 的引用
+			func(lw *LevelWriter[T]) *LevelWriter[T] {
+				lw.w.Reset(nil)
+				return lw
+			},
+			opts...,
+		)
+	}
+	return p
+}
+
+// Get 借出一个写入 w 的压缩器，level 非法时返回错误
+func (p *WriterPool[T]) Get(w io.Writer, level int) (*LevelWriter[T], error) {
+	if level < minLevel || level > maxLevel {
+		return nil, fmt.Errorf("buffer: invalid compression level: %d", level)
+	}
+	lw := p.pools[level-minLevel].Get()
+	lw.w.Reset(w)
+	return lw, nil
+}
+
+// Put 归还压缩器，按 Get 时的等级放回对应的桶
+// 调用方负责在归还前 Close (否则尾部数据不会刷出)
+func (p *WriterPool[T]) Put(lw *LevelWriter[T]) {
+	if lw == nil {
+		return
+	}
+	p.pools[lw.level-minLevel].Put(lw)
+}
+
+// NewGzipWriterPool 创建 *gzip.Writer 专用池
+func NewGzipWriterPool(opts ...*Option) *WriterPool[*gzip.Writer] {
+	return newWriterPool(func(level int) *gzip.Writer {
+		zw, _ := gzip.NewWriterLevel(nil, level) // level 已在 Get 中校验
+		return zw
+	}, opts...)
+}
+
+// NewFlateWriterPool 创建 *flate.Writer 专用池
+func NewFlateWriterPool(opts ...*Option) *WriterPool[*flate.Writer] {
+	return newWriterPool(func(level int) *flate.Writer {
+		zw, _ := flate.NewWriter(nil, level)
+		return zw
+	}, opts...)
+}
+
+// NewZlibWriterPool 创建 *zlib.Writer 专用池
+func NewZlibWriterPool(opts ...*Option) *WriterPool[*zlib.Writer] {
+	return newWriterPool(func(level int) *zlib.Writer {
+		zw, _ := zlib.NewWriterLevel(nil, level)
+		return zw
+	}, opts...)
+}
+
+// ReaderPool 解压器池，解压不区分等级，一个 Pool 即可
+type ReaderPool[T any] struct {
+	pool  *Pool[T]
+	reset func(T, io.Reader) error
+}
+
+func newReaderPool[T any](makeFunc func() T, reset func(T, io.Reader) error, opts ...*Option) *ReaderPool[T] {
+	return &ReaderPool[T]{
+		pool: NewObjectPool(
+			makeFunc,
+			// reset: 用 eofReader 断开对上一个 src 的引用，错误无意义直接忽略
+			func(r T) T {
+				_ = reset(r, eofReader{})
+				return r
+			},
+			opts...,
+		),
+		reset: reset,
+	}
+}
+
+// Get 借出一个读取 r 的解压器
+// gzip/zlib 会在 Reset 时读取 header，header 非法时返回错误，解压器已自动归还
+func (p *ReaderPool[T]) Get(r io.Reader) (T, error) {
+	zr := p.pool.Get()
+	if err := p.reset(zr, r); err != nil {
+		p.pool.Put(zr)
+		var zero T
+		return zero, err
+	}
+	return zr, nil
+}
+
+// Put 归还解压器
+func (p *ReaderPool[T]) Put(zr T) {
+	p.pool.Put(zr)
+}
+
+// NewGzipReaderPool 创建 *gzip.Reader 专用池
+func NewGzipReaderPool(opts ...*Option) *ReaderPool[*gzip.Reader] {
+	return newReaderPool(
+		// 零值 gzip.Reader 可以直接 Reset
+		func() *gzip.Reader {
+			return new(gzip.Reader)
+		},
+		func(zr *gzip.Reader, r io.Reader) error {
+			return zr.Reset(r)
+		},
+		opts...,
+	)
+}
+
+// NewFlateReaderPool 创建 flate 解压器专用池
+func NewFlateReaderPool(opts ...*Option) *ReaderPool[io.ReadCloser] {
+	return newReaderPool(
+		func() io.ReadCloser {
+			return flate.NewReader(eofReader{})
+		},
+		func(zr io.ReadCloser, r io.Reader) error {
+			return zr.(flate.Resetter).Reset(r, nil)
+		},
+		opts...,
+	)
+}
+
+// NewZlibReaderPool 创建 zlib 解压器专用池
+func NewZlibReaderPool(opts ...*Option) *ReaderPool[io.ReadCloser] {
+	return newReaderPool(
+		func() io.ReadCloser {
+			zr, _ := zlib.NewReader(bytes.NewReader(zlibEmptyStream))
+			return zr
+		},
+		func(zr io.ReadCloser, r io.Reader) error {
+			return zr.(zlib.Resetter).Reset(r, nil)
+		},
+		opts...,
+	)
+}
+
+// -----------------------------------------------------------------------------
+// 便捷函数：同时从池中借用编解码器和 *bytes.Buffer
+// -----------------------------------------------------------------------------
+
+var (
+	defaultGzipWriters = NewGzipWriterPool()
+	defaultGzipReaders = NewGzipReaderPool()
+)
+
+// CompressTo 将 src 以 gzip 格式压缩后写入 dst
+// 压缩结果先写入池化的 *bytes.Buffer，最后一次性写给 dst，返回写入 dst 的字节数
+func CompressTo(dst io.Writer, src io.Reader, level int) (int64, error) {
+	buf := defaultBufferPool.Get()
+	defer defaultBufferPool.Put(buf)
+
+	zw, err := defaultGzipWriters.Get(buf, level)
+	if err != nil {
+		return 0, err
+	}
+	defer defaultGzipWriters.Put(zw)
+
+	if _, err := Copy(zw, src); err != nil {
+		return 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+	// 不用 buf.WriteTo：它会清空 Len，Put 时校准就统计不到实际用量
+	n, err := dst.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// DecompressTo 将 gzip 格式的 src 解压后写入 dst，返回写入 dst 的字节数
+func DecompressTo(dst io.Writer, src io.Reader) (int64, error) {
+	zr, err := defaultGzipReaders.Get(src)
+	if err != nil {
+		return 0, err
+	}
+	defer defaultGzipReaders.Put(zr)
+
+	buf := defaultBufferPool.Get()
+	defer defaultBufferPool.Put(buf)
+
+	if _, err := buf.ReadFrom(zr); err != nil {
+		return 0, err
+	}
+	n, err := dst.Write(buf.Bytes())
+	return int64(n), err
+}
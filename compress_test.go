@@ -0,0 +1,126 @@
+package buffer
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestCompressRoundTrip 测试 CompressTo/DecompressTo 往返
+func TestCompressRoundTrip(t *testing.T) {
+	src := strings.Repeat("hello gzip pool ", 1000)
+
+	for level := gzip.HuffmanOnly; level <= gzip.BestCompression; level++ {
+		var compressed, plain bytes.Buffer
+		if _, err := CompressTo(&compressed, strings.NewReader(src), level); err != nil {
+			t.Fatalf("level %d: CompressTo: %v", level, err)
+		}
+		if _, err := DecompressTo(&plain, &compressed); err != nil {
+			t.Fatalf("level %d: DecompressTo: %v", level, err)
+		}
+		if plain.String() != src {
+			t.Fatalf("level %d: round trip mismatch", level)
+		}
+	}
+}
+
+// TestCompressInvalidLevel 测试非法压缩等级
+func TestCompressInvalidLevel(t *testing.T) {
+	if _, err := CompressTo(io.Discard, strings.NewReader("x"), 42); err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+}
+
+// TestCodecPoolsReuse 测试 flate/zlib 编解码器归还后可复用
+func TestCodecPoolsReuse(t *testing.T) {
+	fw := NewFlateWriterPool()
+	fr := NewFlateReaderPool()
+	zw := NewZlibWriterPool()
+	zr := NewZlibReaderPool()
+
+	for i := 0; i < 3; i++ {
+		var buf bytes.Buffer
+
+		w, err := fw.Get(&buf, flate.BestSpeed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte("flate data"))
+		w.Close()
+		fw.Put(w)
+
+		r, err := fr.Get(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := io.ReadAll(r)
+		fr.Put(r)
+		if err != nil || string(out) != "flate data" {
+			t.Fatalf("flate round %d: got %q, err %v", i, out, err)
+		}
+
+		buf.Reset()
+		w2, err := zw.Get(&buf, flate.DefaultCompression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w2.Write([]byte("zlib data"))
+		w2.Close()
+		zw.Put(w2)
+
+		r2, err := zr.Get(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err = io.ReadAll(r2)
+		zr.Put(r2)
+		if err != nil || string(out) != "zlib data" {
+			t.Fatalf("zlib round %d: got %q, err %v", i, out, err)
+		}
+	}
+}
+
+// TestWriterPoolLevel 测试压缩器按创建时的等级归还，不会混入其它等级的桶
+func TestWriterPoolLevel(t *testing.T) {
+	p := NewGzipWriterPool()
+	w, err := p.Get(io.Discard, gzip.BestSpeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	p.Put(w)
+
+	if st := p.pools[gzip.BestSpeed-minLevel].Stats(); st.Idle != 1 {
+		t.Fatalf("expected writer back in BestSpeed bucket, idle %d", st.Idle)
+	}
+	if st := p.pools[gzip.BestCompression-minLevel].Stats(); st.Idle != 0 {
+		t.Fatalf("BestCompression bucket should be empty, idle %d", st.Idle)
+	}
+	w2, _ := p.Get(io.Discard, gzip.BestSpeed)
+	if w2 != w || w2.Level() != gzip.BestSpeed {
+		t.Fatalf("expected the same BestSpeed writer to be reused, got level %d", w2.Level())
+	}
+	w2.Close()
+	p.Put(w2)
+}
+
+// TestGzipReaderBadHeader 测试非法 header 返回错误
+func TestGzipReaderBadHeader(t *testing.T) {
+	p := NewGzipReaderPool()
+	if _, err := p.Get(strings.NewReader("not gzip")); err == nil {
+		t.Fatal("expected error for invalid gzip header")
+	}
+	// 出错后池子仍可用
+	var buf bytes.Buffer
+	if _, err := CompressTo(&buf, strings.NewReader("ok"), gzip.DefaultCompression); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := p.Get(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Put(zr)
+}
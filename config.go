@@ -0,0 +1,310 @@
+package buffer
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// 人类可读的尺寸
+// -----------------------------------------------------------------------------
+
+// Size 字节数，JSON 中既可以写数字，也可以写 "64MiB" 这样的字符串
+type Size uint64
+
+var sizeUnits = []struct {
+	suffix string
+	mult   uint64
+}{
+	// 长后缀在前，避免 "MiB" 被 "B" 先匹配
+	{"kib", 1 << 10}, {"mib", 1 << 20}, {"gib", 1 << 30}, {"tib", 1 << 40},
+	{"kb", 1e3}, {"mb", 1e6}, {"gb", 1e9}, {"tb", 1e12},
+	{"k", 1 << 10}, {"m", 1 << 20}, {"g", 1 << 30}, {"t", 1 << 40},
+	{"b", 1},
+}
+
+// ParseSize 解析尺寸，不区分大小写：
+// 纯数字为字节；KiB/MiB/GiB/TiB 与 K/M/G/T 为 1024 进制；KB/MB/GB/TB 为 1000 进制
+func ParseSize(s string) (uint64, error) {
+	str := strings.ToLower(strings.TrimSpace(s))
+	mult := uint64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(str, u.suffix) {
+			str = strings.TrimSpace(strings.TrimSuffix(str, u.suffix))
+			mult = u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("buffer: invalid size %q", s)
+	}
+	if n > 0 && mult > ^uint64(0)/n {
+		return 0, fmt.Errorf("buffer: size %q overflows uint64", s)
+	}
+	return n * mult, nil
+}
+
+// FormatSize 格式化尺寸，能整除时使用最大的 1024 进制单位，如 64<<20 => "64MiB"
+func FormatSize(n uint64) string {
+	for _, u := range []struct {
+		suffix string
+		mult   uint64
+	}{{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10}} {
+		if n >= u.mult && n%u.mult == 0 {
+			return strconv.FormatUint(n/u.mult, 10) + u.suffix
+		}
+	}
+	return strconv.FormatUint(n, 10) + "B"
+}
+
+func (s Size) String() string {
+	return FormatSize(uint64(s))
+}
+
+func (s Size) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *Size) UnmarshalJSON(data []byte) error {
+	var n uint64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*s = Size(n)
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("buffer: size must be a number or a string, got %s", data)
+	}
+	n, err := ParseSize(str)
+	if err != nil {
+		return err
+	}
+	*s = Size(n)
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// JSON
+// -----------------------------------------------------------------------------
+
+// optionJSON Option 的 JSON 形式，尺寸字段使用 Size
+type optionJSON struct {
+	CalibratePeriod *uint64   `json:"calibrate_period,omitempty"`
+	MaxPercent      *float64  `json:"max_percent,omitempty"`
+	MinSize         *Size     `json:"min_size,omitempty"`
+	MaxSize         *Size     `json:"max_size,omitempty"`
+	CalibratedSz    *Size     `json:"calibrated_size,omitempty"`
+	ClearOnReset    *bool     `json:"clear_on_reset,omitempty"`
+	LeakCheck       *bool     `json:"leak_check,omitempty"`
+	HugePage        *bool     `json:"huge_page,omitempty"`
+	Align           *Size     `json:"align,omitempty"`
+	Snapshot        *Snapshot `json:"snapshot,omitempty"`
+	RingMinCap      *int      `json:"ring_min_cap,omitempty"`
+	RingMaxCap      *int      `json:"ring_max_cap,omitempty"`
+	EmaUpFactor     *float64  `json:"ema_up_factor,omitempty"`
+	EmaDownFactor   *float64  `json:"ema_down_factor,omitempty"`
+	Overflow        *bool     `json:"overflow,omitempty"`
+}
+
+// MarshalJSON 只输出已设置的字段，尺寸输出为 "64MiB" 形式
+func (o Option) MarshalJSON() ([]byte, error) {
+	return json.Marshal(optionJSON{
+		CalibratePeriod: o.CalibratePeriod,
+		MaxPercent:      o.MaxPercent,
+		MinSize:         (*Size)(o.MinSize),
+		MaxSize:         (*Size)(o.MaxSize),
+		CalibratedSz:    (*Size)(o.CalibratedSz),
+		ClearOnReset:    o.ClearOnReset,
+		LeakCheck:       o.LeakCheck,
+		HugePage:        o.HugePage,
+		Align:           (*Size)(o.Align),
+		Snapshot:        o.Snapshot,
+		RingMinCap:      o.RingMinCap,
+		RingMaxCap:      o.RingMaxCap,
+		EmaUpFactor:     o.EmaUpFactor,
+		EmaDownFactor:   o.EmaDownFactor,
+		Overflow:        o.Overflow,
+	})
+}
+
+// UnmarshalJSON 尺寸字段接受数字或 "64MiB" 形式的字符串，未出现的字段保持 nil
+func (o *Option) UnmarshalJSON(data []byte) error {
+	var j optionJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	*o = Option{
+		CalibratePeriod: j.CalibratePeriod,
+		MaxPercent:      j.MaxPercent,
+		MinSize:         (*uint64)(j.MinSize),
+		MaxSize:         (*uint64)(j.MaxSize),
+		CalibratedSz:    (*uint64)(j.CalibratedSz),
+		ClearOnReset:    j.ClearOnReset,
+		LeakCheck:       j.LeakCheck,
+		HugePage:        j.HugePage,
+		Align:           (*uint64)(j.Align),
+		Snapshot:        j.Snapshot,
+		RingMinCap:      j.RingMinCap,
+		RingMaxCap:      j.RingMaxCap,
+		EmaUpFactor:     j.EmaUpFactor,
+		EmaDownFactor:   j.EmaDownFactor,
+		Overflow:        j.Overflow,
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// 环境变量 / 命令行参数
+// -----------------------------------------------------------------------------
+
+// optionFields 可以通过环境变量和命令行设置的字段 (Snapshot 除外)
+// name 为 snake_case，环境变量为 PREFIX_NAME 大写，命令行参数为 prefix + name 中划线形式
+var optionFields = []struct {
+	name  string
+	usage string
+	value func(o *Option) flag.Value
+}{
+	{"calibrate_period", "Put calls between calibrations", func(o *Option) flag.Value { return &uintValue{&o.CalibratePeriod} }},
+	{"max_percent", "discard buffers whose cap exceeds calibrated size * max_percent", func(o *Option) flag.Value { return &floatValue{&o.MaxPercent} }},
+	{"min_size", "minimum buffer size, e.g. 512 or 4KiB", func(o *Option) flag.Value { return &sizeValue{&o.MinSize} }},
+	{"max_size", "maximum buffer size, e.g. 64MiB", func(o *Option) flag.Value { return &sizeValue{&o.MaxSize} }},
+	{"calibrated_size", "initial calibrated size, e.g. 1KiB", func(o *Option) flag.Value { return &sizeValue{&o.CalibratedSz} }},
+	{"clear_on_reset", "clear slice elements on reset", func(o *Option) flag.Value { return &boolValue{&o.ClearOnReset} }},
+	{"leak_check", "attach finalizers to handles to catch leaks", func(o *Option) flag.Value { return &boolValue{&o.LeakCheck} }},
+	{"huge_page", "madvise(MADV_HUGEPAGE) for mmap pools", func(o *Option) flag.Value { return &boolValue{&o.HugePage} }},
+	{"align", "round calibrated size up to this block size", func(o *Option) flag.Value { return &sizeValue{&o.Align} }},
+	{"ring_min_cap", "minimum ring capacity", func(o *Option) flag.Value { return &intValue{&o.RingMinCap} }},
+	{"ring_max_cap", "maximum ring capacity", func(o *Option) flag.Value { return &intValue{&o.RingMaxCap} }},
+	{"ema_up_factor", "history weight when the calibrated size grows (0~1)", func(o *Option) flag.Value { return &floatValue{&o.EmaUpFactor} }},
+	{"ema_down_factor", "history weight when the calibrated size shrinks (0~1)", func(o *Option) flag.Value { return &floatValue{&o.EmaDownFactor} }},
+	{"overflow", "spill buffers that do not fit in the ring into a sync.Pool", func(o *Option) flag.Value { return &boolValue{&o.Overflow} }},
+}
+
+// FromEnv 从环境变量读取配置，变量名为 prefix + "_" + 字段名大写，如 APP_HTTPPOOL_MAX_SIZE
+// 未设置的变量不修改对应字段；与 SetXxx 一样支持 nil 接收者
+func (o *Option) FromEnv(prefix string) (*Option, error) {
+	if o == nil {
+		o = &Option{}
+	}
+	var errs []error
+	for _, f := range optionFields {
+		key := strings.ToUpper(prefix + "_" + f.name)
+		v, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+		if err := f.value(o).Set(v); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return o, errors.Join(errs...)
+}
+
+// RegisterFlags 把所有字段注册为命令行参数，参数名为 prefix + 字段名 (中划线)，如 prefix "httppool." => -httppool.max-size
+// fs.Parse 之后只有出现在命令行上的参数会写入 o，o 不能为 nil
+func (o *Option) RegisterFlags(fs *flag.FlagSet, prefix string) {
+	for _, f := range optionFields {
+		fs.Var(f.value(o), prefix+strings.ReplaceAll(f.name, "_", "-"), f.usage)
+	}
+}
+
+// 以下 flag.Value 只在 Set 时写入，保持未设置字段为 nil，与 Option 的合并语义一致
+
+type sizeValue struct{ p **uint64 }
+
+func (v *sizeValue) String() string {
+	if v.p == nil || *v.p == nil {
+		return ""
+	}
+	return FormatSize(**v.p)
+}
+
+func (v *sizeValue) Set(s string) error {
+	n, err := ParseSize(s)
+	if err != nil {
+		return err
+	}
+	*v.p = &n
+	return nil
+}
+
+type uintValue struct{ p **uint64 }
+
+func (v *uintValue) String() string {
+	if v.p == nil || *v.p == nil {
+		return ""
+	}
+	return strconv.FormatUint(**v.p, 10)
+}
+
+func (v *uintValue) Set(s string) error {
+	n, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return err
+	}
+	*v.p = &n
+	return nil
+}
+
+type intValue struct{ p **int }
+
+func (v *intValue) String() string {
+	if v.p == nil || *v.p == nil {
+		return ""
+	}
+	return strconv.Itoa(**v.p)
+}
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	*v.p = &n
+	return nil
+}
+
+type floatValue struct{ p **float64 }
+
+func (v *floatValue) String() string {
+	if v.p == nil || *v.p == nil {
+		return ""
+	}
+	return strconv.FormatFloat(**v.p, 'g', -1, 64)
+}
+
+func (v *floatValue) Set(s string) error {
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return err
+	}
+	*v.p = &n
+	return nil
+}
+
+type boolValue struct{ p **bool }
+
+func (v *boolValue) String() string {
+	if v.p == nil || *v.p == nil {
+		return ""
+	}
+	return strconv.FormatBool(**v.p)
+}
+
+func (v *boolValue) Set(s string) error {
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	*v.p = &b
+	return nil
+}
+
+// IsBoolFlag 允许命令行直接写 -leak-check 而不带 =true
+func (v *boolValue) IsBoolFlag() bool { return true }
@@ -0,0 +1,103 @@
+package buffer
+
+import (
+	"encoding/json"
+	"flag"
+	"testing"
+)
+
+// TestParseSize 测试尺寸解析
+func TestParseSize(t *testing.T) {
+	tests := map[string]uint64{
+		"512":    512,
+		"512B":   512,
+		"4KiB":   4 << 10,
+		"4k":     4 << 10,
+		"64MiB":  64 << 20,
+		"64 mib": 64 << 20,
+		"1GB":    1e9,
+		"2G":     2 << 30,
+	}
+	for in, want := range tests {
+		got, err := ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "MiB", "-1", "1.5MiB", "99999999999TiB"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("ParseSize(%q) expected error", in)
+		}
+	}
+	if s := FormatSize(64 << 20); s != "64MiB" {
+		t.Errorf("FormatSize = %q", s)
+	}
+	if s := FormatSize(1500); s != "1500B" {
+		t.Errorf("FormatSize = %q", s)
+	}
+}
+
+// TestOptionJSON 测试 JSON 往返与人类可读尺寸
+func TestOptionJSON(t *testing.T) {
+	opt := Options().SetMaxSize(64 << 20).SetMinSize(512).SetMaxPercent(1.5).SetRingMaxCap(128)
+	data, err := json.Marshal(opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"max_percent":1.5,"min_size":"512B","max_size":"64MiB","ring_max_cap":128}`
+	if string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+
+	var got Option
+	if err := json.Unmarshal([]byte(`{"max_size":"16MiB","min_size":1024,"leak_check":true}`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if *got.MaxSize != 16<<20 || *got.MinSize != 1024 || !*got.LeakCheck || got.MaxPercent != nil {
+		t.Fatalf("unexpected option %+v", got)
+	}
+
+	if err := json.Unmarshal([]byte(`{"max_size":"lots"}`), &got); err == nil {
+		t.Fatal("expected error for invalid size")
+	}
+}
+
+// TestOptionFromEnv 测试从环境变量读取
+func TestOptionFromEnv(t *testing.T) {
+	t.Setenv("APP_HTTPPOOL_MAX_SIZE", "32MiB")
+	t.Setenv("APP_HTTPPOOL_MAX_PERCENT", "2")
+	t.Setenv("APP_HTTPPOOL_RING_MAX_CAP", "64")
+
+	var opt *Option
+	opt, err := opt.FromEnv("APP_HTTPPOOL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *opt.MaxSize != 32<<20 || *opt.MaxPercent != 2 || *opt.RingMaxCap != 64 || opt.MinSize != nil {
+		t.Fatalf("unexpected option %+v", opt)
+	}
+
+	t.Setenv("APP_HTTPPOOL_CALIBRATE_PERIOD", "often")
+	if _, err := Options().FromEnv("APP_HTTPPOOL"); err == nil {
+		t.Fatal("expected error for invalid value")
+	}
+}
+
+// TestOptionRegisterFlags 测试命令行参数
+func TestOptionRegisterFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opt := Options()
+	opt.RegisterFlags(fs, "httppool.")
+
+	if err := fs.Parse([]string{"-httppool.max-size=8MiB", "-httppool.leak-check", "-httppool.ring-min-cap", "4"}); err != nil {
+		t.Fatal(err)
+	}
+	if *opt.MaxSize != 8<<20 || !*opt.LeakCheck || *opt.RingMinCap != 4 || opt.MinSize != nil {
+		t.Fatalf("unexpected option %+v", opt)
+	}
+
+	p := NewBufferPool(opt)
+	if p.Stats().RingCap != 4 {
+		t.Fatalf("expected ring cap 4, got %d", p.Stats().RingCap)
+	}
+}
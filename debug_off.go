@@ -0,0 +1,6 @@
+//go:build !buffer_debug
+
+package buffer
+
+// debug 误用检测开关，使用 -tags buffer_debug 编译时打开
+const debug = false
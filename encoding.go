@@ -0,0 +1,65 @@
+package buffer
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+)
+
+// 每种编码各自一个池：JSON 和模板输出的尺寸分布差别很大，共用一个池会互相干扰校准
+var (
+	jsonPool     = NewBufferPool()
+	xmlPool      = NewBufferPool()
+	gobPool      = NewBufferPool()
+	templatePool = NewBufferPool()
+)
+
+// MarshalJSON 与 json.Marshal 输出一致，但结果写入池化的 buffer
+// 用完后调用 Release 归还，出错时 buffer 已自动归还
+func MarshalJSON(v any) (*Handle[*bytes.Buffer], error) {
+	return encodeWith(jsonPool, func(buf *bytes.Buffer) error {
+		if err := json.NewEncoder(buf).Encode(v); err != nil {
+			return err
+		}
+		// Encoder 会追加换行，去掉以保持与 json.Marshal 一致
+		buf.Truncate(buf.Len() - 1)
+		return nil
+	})
+}
+
+// EncodeXML 与 xml.Marshal 输出一致，但结果写入池化的 buffer
+func EncodeXML(v any) (*Handle[*bytes.Buffer], error) {
+	return encodeWith(xmlPool, func(buf *bytes.Buffer) error {
+		return xml.NewEncoder(buf).Encode(v)
+	})
+}
+
+// EncodeGob 将 v 编码为独立的 gob 流 (包含类型信息)，结果写入池化的 buffer
+func EncodeGob(v any) (*Handle[*bytes.Buffer], error) {
+	return encodeWith(gobPool, func(buf *bytes.Buffer) error {
+		return gob.NewEncoder(buf).Encode(v)
+	})
+}
+
+// Executor text/template 和 html/template 的公共方法
+type Executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// ExecuteTemplate 渲染模板到池化的 buffer，t 可以是 *text/template.Template 或 *html/template.Template
+func ExecuteTemplate(t Executor, data any) (*Handle[*bytes.Buffer], error) {
+	return encodeWith(templatePool, func(buf *bytes.Buffer) error {
+		return t.Execute(buf, data)
+	})
+}
+
+func encodeWith(p *Pool[*bytes.Buffer], encode func(*bytes.Buffer) error) (*Handle[*bytes.Buffer], error) {
+	h := p.Acquire()
+	if err := encode(h.Value()); err != nil {
+		h.Release()
+		return nil, err
+	}
+	return h, nil
+}
@@ -0,0 +1,82 @@
+package buffer
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"html/template"
+	"testing"
+)
+
+type encodingSample struct {
+	Name string `json:"name" xml:"name"`
+	Tags []string
+}
+
+// TestMarshalJSON 测试输出与 json.Marshal 一致
+func TestMarshalJSON(t *testing.T) {
+	v := encodingSample{Name: "<pool>", Tags: []string{"a", "b"}}
+	want, _ := json.Marshal(v)
+
+	h, err := MarshalJSON(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Release()
+	if !bytes.Equal(h.Value().Bytes(), want) {
+		t.Fatalf("got %s, want %s", h.Value().Bytes(), want)
+	}
+
+	if _, err := MarshalJSON(make(chan int)); err == nil {
+		t.Fatal("expected error for unsupported type")
+	}
+}
+
+// TestEncodeXML 测试输出与 xml.Marshal 一致
+func TestEncodeXML(t *testing.T) {
+	v := encodingSample{Name: "pool"}
+	want, _ := xml.Marshal(v)
+
+	h, err := EncodeXML(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Release()
+	if !bytes.Equal(h.Value().Bytes(), want) {
+		t.Fatalf("got %s, want %s", h.Value().Bytes(), want)
+	}
+}
+
+// TestEncodeGob 测试 gob 往返
+func TestEncodeGob(t *testing.T) {
+	v := encodingSample{Name: "pool", Tags: []string{"x"}}
+
+	h, err := EncodeGob(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Release()
+
+	var got encodingSample
+	if err := gob.NewDecoder(h.Value()).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != v.Name || len(got.Tags) != 1 {
+		t.Fatalf("unexpected decode result %+v", got)
+	}
+}
+
+// TestExecuteTemplate 测试模板渲染
+func TestExecuteTemplate(t *testing.T) {
+	tpl := template.Must(template.New("t").Parse("<p>{{.Name}}</p>"))
+
+	h, err := ExecuteTemplate(tpl, encodingSample{Name: "<b>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Release()
+	if got := h.Value().String(); got != "<p>&lt;b&gt;</p>" {
+		t.Fatalf("unexpected output %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+package buffer
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// Handle 状态
+const (
+	handleOwned    = iota // 持有中
+	handleReleased        // 已归还
+	handleDetached        // 已脱离，调用方永久持有
+)
+
+// Handle 对池化对象的独占持有，防止错误路径上 Get/Put 不配对
+// 典型用法：h := p.Acquire(); defer h.Release()
+type Handle[T any] struct {
+	val    T
+	pool   *Pool[T]
+	state  atomic.Uint32
+	scoped bool // 由 Scope 登记，debug 构建下需要同步追踪状态
+}
+
+// Acquire 从池中取一个对象并包装成 Handle
+// 开启 LeakCheck 时挂 finalizer：Handle 被 GC 回收但从未 Release/Detach，则记一次泄漏
+// finalizer 不会把对象放回池中：Value() 取出的对象可能仍在使用，归还会导致它被其它调用方复用
+func (p *Pool[T]) Acquire() *Handle[T] {
+	h := &Handle[T]{val: p.Get(), pool: p}
+	if p.leakCheck {
+		runtime.SetFinalizer(h, (*Handle[T]).finalize)
+	}
+	return h
+}
+
+// Leaks 返回被 GC 回收但从未 Release/Detach 的 Handle 数量，非 0 说明有代码路径忘记 Release
+func (p *Pool[T]) Leaks() uint64 {
+	return p.leaks.Load()
+}
+
+// Value 返回持有的对象，Release 之后不能再访问；使用期间必须保持 Handle 可达
+func (h *Handle[T]) Value() T {
+	return h.val
+}
+
+// Set 替换持有的对象，用于 []byte append 后 slice header 变化的场景
+func (h *Handle[T]) Set(v T) {
+	if debug && h.scoped {
+		untrackScoped(h.val)
+		trackScoped(v)
+	}
+	h.val = v
+}
+
+// Release 归还对象，可重复调用，只有第一次生效
+func (h *Handle[T]) Release() {
+	if !h.state.CompareAndSwap(handleOwned, handleReleased) {
+		return
+	}
+	if h.pool.leakCheck {
+		runtime.SetFinalizer(h, nil)
+	}
+	v := h.val
+	var zero T
+	h.val = zero
+	if debug && h.scoped {
+		untrackScoped(v)
+	}
+	h.pool.Put(v)
+}
+
+// Detach 放弃归还，调用方永久持有返回的对象
+// 已 Release 的 Handle 返回零值
+func (h *Handle[T]) Detach() T {
+	if !h.state.CompareAndSwap(handleOwned, handleDetached) {
+		var zero T
+		return zero
+	}
+	if h.pool.leakCheck {
+		runtime.SetFinalizer(h, nil)
+	}
+	v := h.val
+	var zero T
+	h.val = zero
+	if debug && h.scoped {
+		untrackScoped(v)
+	}
+	return v
+}
+
+// finalize 只记录泄漏，对象交给 GC
+func (h *Handle[T]) finalize() {
+	if h.state.CompareAndSwap(handleOwned, handleReleased) {
+		h.pool.leaks.Add(1)
+	}
+}
@@ -0,0 +1,68 @@
+package buffer
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestHandleRelease 测试 Release 幂等
+func TestHandleRelease(t *testing.T) {
+	p := NewBufferPool()
+
+	h := p.Acquire()
+	buf := h.Value()
+	buf.WriteString("hello")
+	h.Release()
+	h.Release() // 重复调用应该安全
+
+	if h.Value() != nil {
+		t.Fatal("expected nil value after Release")
+	}
+
+	h2 := p.Acquire()
+	if h2.Value() != buf {
+		t.Fatal("expected released buffer to be reused")
+	}
+	h2.Release()
+}
+
+// TestHandleDetach 测试 Detach 后不归还
+func TestHandleDetach(t *testing.T) {
+	p := NewBytePool()
+
+	h := p.Acquire()
+	h.Set(append(h.Value(), "owned"...))
+	b := h.Detach()
+	h.Release() // Detach 之后 Release 无效
+
+	if string(b) != "owned" {
+		t.Fatalf("unexpected detached value %q", b)
+	}
+	if h.Detach() != nil {
+		t.Fatal("second Detach should return zero value")
+	}
+}
+
+// TestHandleLeakCheck 测试 finalizer 只记录泄漏，不归还仍可能在使用的对象
+func TestHandleLeakCheck(t *testing.T) {
+	p := NewBufferPool(Options().SetLeakCheck(true))
+
+	// Handle 不可达，但取出的对象仍在使用
+	buf := p.Acquire().Value()
+	buf.WriteString("leaked")
+
+	for i := 0; i < 10 && p.Leaks() == 0; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if p.Leaks() != 1 {
+		t.Fatalf("expected 1 leak, got %d", p.Leaks())
+	}
+	if buf.String() != "leaked" {
+		t.Fatalf("leaked buffer was reset: %q", buf.String())
+	}
+	if got := p.Get(); got == buf {
+		t.Fatal("leaked buffer should not be handed out again")
+	}
+}
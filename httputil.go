@@ -0,0 +1,32 @@
+package buffer
+
+import "net/http/httputil"
+
+var _ httputil.BufferPool = (*HTTPBufferPool)(nil)
+
+// HTTPBufferPool 实现 httputil.BufferPool，供 httputil.ReverseProxy 复制响应体使用
+// ReverseProxy 把 Get 的结果整体当作 io.CopyBuffer 的 scratch，要求 len == cap，而不是 [:0]
+type HTTPBufferPool struct {
+	pool *Pool[[]byte]
+}
+
+// NewHTTPBufferPool 创建 ReverseProxy 专用的 buffer 池，默认 32KB (与 ReverseProxy 自己分配的大小一致)
+func NewHTTPBufferPool(opts ...*Option) *HTTPBufferPool {
+	return &HTTPBufferPool{
+		pool: NewBytePool(append([]*Option{
+			Options().SetCalibratedSz(32 << 10),
+		}, opts...)...),
+	}
+}
+
+// Get 返回 len == cap 的 scratch
+func (p *HTTPBufferPool) Get() []byte {
+	b := p.pool.Get()
+	return b[:cap(b)]
+}
+
+// Put 归还 scratch
+// ReverseProxy 不告诉我们实际用了多少，整块计入用量，校准会稳定在当前大小
+func (p *HTTPBufferPool) Put(b []byte) {
+	p.pool.Put(b)
+}
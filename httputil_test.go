@@ -0,0 +1,81 @@
+package buffer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// countingBufferPool 统计 ReverseProxy 的 Get/Put 调用
+type countingBufferPool struct {
+	*HTTPBufferPool
+	gets, puts atomic.Int32
+}
+
+func (c *countingBufferPool) Get() []byte {
+	c.gets.Add(1)
+	return c.HTTPBufferPool.Get()
+}
+
+func (c *countingBufferPool) Put(b []byte) {
+	c.puts.Add(1)
+	c.HTTPBufferPool.Put(b)
+}
+
+// TestHTTPBufferPoolGet 测试 Get 返回满长度切片
+func TestHTTPBufferPoolGet(t *testing.T) {
+	p := NewHTTPBufferPool()
+	b := p.Get()
+	if len(b) == 0 || len(b) != cap(b) {
+		t.Fatalf("expected len == cap > 0, got len %d cap %d", len(b), cap(b))
+	}
+	p.Put(b)
+
+	b2 := p.Get()
+	if len(b2) != cap(b2) {
+		t.Fatalf("reused buffer: expected len == cap, got len %d cap %d", len(b2), cap(b2))
+	}
+	p.Put(b2)
+}
+
+// TestHTTPBufferPoolReverseProxy 测试作为 ReverseProxy.BufferPool 使用
+func TestHTTPBufferPoolReverseProxy(t *testing.T) {
+	body := strings.Repeat("proxied ", 50000) // 大于 32KB，需要多次拷贝
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, body)
+	}))
+	defer backend.Close()
+
+	target, _ := url.Parse(backend.URL)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	pool := &countingBufferPool{HTTPBufferPool: NewHTTPBufferPool()}
+	proxy.BufferPool = pool
+
+	front := httptest.NewServer(proxy)
+	defer front.Close()
+
+	for i := 0; i < 3; i++ {
+		resp, err := http.Get(front.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != body {
+			t.Fatalf("request %d: body mismatch, got %d bytes", i, len(got))
+		}
+	}
+
+	if pool.gets.Load() == 0 || pool.gets.Load() != pool.puts.Load() {
+		t.Fatalf("unbalanced pool usage: gets %d, puts %d", pool.gets.Load(), pool.puts.Load())
+	}
+}
@@ -0,0 +1,56 @@
+package buffer
+
+import (
+	"bytes"
+	"io"
+)
+
+// ReadAll 读取 r 的全部内容到池化的 *bytes.Buffer
+// 用完后必须调用 PutBuffer 归还，归还时的 Len 会参与校准，下次预分配更贴近实际大小
+func ReadAll(r io.Reader) (*bytes.Buffer, error) {
+	buf := defaultBufferPool.Get()
+	// 复用的 buffer 容量可能小于最新校准值，先撑到校准值，减少 ReadFrom 中的扩容
+	if sz := int(defaultBufferPool.CalibratedSize()); buf.Cap() < sz {
+		buf.Grow(sz)
+	}
+	_, err := buf.ReadFrom(r)
+	return buf, err
+}
+
+// PutBuffer 归还 ReadAll 返回的 buffer
+func PutBuffer(buf *bytes.Buffer) {
+	defaultBufferPool.Put(buf)
+}
+
+// Copy 等价于 io.Copy，但 scratch 从池中借用
+// src 实现 io.WriterTo 或 dst 实现 io.ReaderFrom 时不需要 scratch，直接走 io.Copy
+func Copy(dst io.Writer, src io.Reader) (int64, error) {
+	if _, ok := src.(io.WriterTo); ok {
+		return io.Copy(dst, src)
+	}
+	if _, ok := dst.(io.ReaderFrom); ok {
+		return io.Copy(dst, src)
+	}
+
+	b := defaultCopyPool.Get()
+	b = b[:cap(b)]
+	n, err := io.CopyBuffer(dst, src, b)
+	// 以实际拷贝量参与校准：大拷贝让 scratch 长大 (上限 MaxSize)；
+	// 介于 MinSize 和校准值之间的拷贝按采样记录，让 scratch 缓慢缩小；
+	// 不超过 MinSize 的拷贝不计入校准，一个周期内全是这种拷贝时校准跳过，scratch 保持原样
+	defaultCopyPool.put(b[:0], uint64(n), uint64(cap(b)))
+	return n, err
+}
+
+// CopyN 等价于 io.CopyN，但 scratch 从池中借用
+func CopyN(dst io.Writer, src io.Reader, n int64) (int64, error) {
+	written, err := Copy(dst, io.LimitReader(src, n))
+	if written == n {
+		return n, nil
+	}
+	if written < n && err == nil {
+		// src 提前结束
+		err = io.EOF
+	}
+	return written, err
+}
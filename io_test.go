@@ -0,0 +1,66 @@
+package buffer
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// onlyReader 隐藏 strings.Reader 的 WriterTo，强制 Copy 走 scratch 路径
+type onlyReader struct{ io.Reader }
+
+// onlyWriter 隐藏 bytes.Buffer 的 ReaderFrom
+type onlyWriter struct{ io.Writer }
+
+// TestReadAll 测试 ReadAll 读取完整内容
+func TestReadAll(t *testing.T) {
+	src := strings.Repeat("readall ", 2000)
+
+	buf, err := ReadAll(onlyReader{strings.NewReader(src)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != src {
+		t.Fatal("content mismatch")
+	}
+	PutBuffer(buf)
+}
+
+// TestCopy 测试 Copy 与 CopyN
+func TestCopy(t *testing.T) {
+	src := strings.Repeat("copy ", 20000)
+
+	var dst bytes.Buffer
+	n, err := Copy(onlyWriter{&dst}, onlyReader{strings.NewReader(src)})
+	if err != nil || n != int64(len(src)) || dst.String() != src {
+		t.Fatalf("Copy: n=%d err=%v", n, err)
+	}
+
+	dst.Reset()
+	n, err = CopyN(onlyWriter{&dst}, onlyReader{strings.NewReader(src)}, 10)
+	if err != nil || n != 10 || dst.String() != src[:10] {
+		t.Fatalf("CopyN: n=%d err=%v", n, err)
+	}
+
+	n, err = CopyN(io.Discard, strings.NewReader("short"), 10)
+	if err != io.EOF || n != 5 {
+		t.Fatalf("CopyN short: n=%d err=%v", n, err)
+	}
+}
+
+// TestCopyCalibration 测试 Copy 以实际拷贝量参与校准
+func TestCopyCalibration(t *testing.T) {
+	before := defaultCopyPool.CalibratedSize()
+	src := make([]byte, 256<<10)
+
+	for i := 0; i < 2000; i++ {
+		Copy(onlyWriter{io.Discard}, onlyReader{bytes.NewReader(src)})
+	}
+
+	after := defaultCopyPool.CalibratedSize()
+	t.Logf("copy scratch calibrated: %d -> %d", before, after)
+	if after <= before {
+		t.Errorf("expected scratch to grow, got %d -> %d", before, after)
+	}
+}
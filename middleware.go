@@ -0,0 +1,132 @@
+package buffer
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+)
+
+// BufferResponse 返回一个缓冲响应体的中间件
+// handler 的输出先写入池化的 *bytes.Buffer，结束时补上 Content-Length 一次性写出；
+// limit > 0 时，缓冲超过 limit 字节 (或 handler 主动 Flush) 就转为流式输出，避免大响应占用内存
+func BufferResponse(next http.Handler, limit int, opts ...*Option) http.Handler {
+	pool := NewBufferPool(opts...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bw := &bufferedResponseWriter{
+			ResponseWriter: w,
+			pool:           pool,
+			buf:            pool.Get(),
+			limit:          limit,
+			head:           r.Method == http.MethodHead,
+		}
+		// handler panic 时也要归还 buffer
+		defer bw.release()
+
+		next.ServeHTTP(bw, r)
+		bw.finish()
+	})
+}
+
+// bufferedResponseWriter 缓冲响应体的 http.ResponseWriter
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	pool  *Pool[*bytes.Buffer]
+	buf   *bytes.Buffer // 转为流式后为 nil
+	limit int
+	head  bool // HEAD 请求
+
+	status      int
+	wroteHeader bool
+}
+
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	// 1xx (如 103 Early Hints) 是中间响应，直接透传，不影响之后的最终状态码；101 切换协议除外
+	if code >= 100 && code <= 199 && code != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	if w.buf == nil {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *bufferedResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buf != nil && w.limit > 0 && w.buf.Len()+len(p) > w.limit {
+		if err := w.spill(); err != nil {
+			return 0, err
+		}
+	}
+	if w.buf == nil {
+		return w.ResponseWriter.Write(p)
+	}
+	return w.buf.Write(p)
+}
+
+// Flush 实现 http.Flusher，handler 主动 Flush 说明需要流式输出，直接转为流式
+func (w *bufferedResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buf != nil {
+		if err := w.spill(); err != nil {
+			return
+		}
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter
+func (w *bufferedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// spill 写出状态码和已缓冲的内容，之后转为流式
+func (w *bufferedResponseWriter) spill() error {
+	buf := w.buf
+	w.buf = nil
+	defer w.pool.Put(buf)
+
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.ResponseWriter.Write(buf.Bytes())
+	return err
+}
+
+// finish handler 正常返回后写出缓冲内容
+func (w *bufferedResponseWriter) finish() {
+	if w.buf == nil {
+		return
+	}
+	if !w.wroteHeader {
+		w.status = http.StatusOK
+	}
+	h := w.Header()
+	// HEAD 请求 handler 通常不写响应体，此时 0 并不是 GET 响应体的长度，与 net/http 一样不设置
+	if h.Get("Content-Length") == "" && bodyAllowed(w.status) && !(w.head && w.buf.Len() == 0) {
+		h.Set("Content-Length", strconv.Itoa(w.buf.Len()))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.buf.Bytes())
+}
+
+// release 归还 buffer，可重复调用
+func (w *bufferedResponseWriter) release() {
+	if w.buf != nil {
+		w.pool.Put(w.buf)
+		w.buf = nil
+	}
+}
+
+// bodyAllowed 1xx/204/304 不允许有响应体，也不能带 Content-Length
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
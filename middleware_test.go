@@ -0,0 +1,143 @@
+package buffer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// TestBufferResponseContentLength 测试缓冲后补上 Content-Length
+func TestBufferResponseContentLength(t *testing.T) {
+	h := BufferResponse(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, "hello ")
+		io.WriteString(w, "world")
+	}), 0)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	if rec.Body.String() != "hello world" {
+		t.Fatalf("unexpected body %q", rec.Body.String())
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != "11" {
+		t.Fatalf("expected Content-Length 11, got %q", cl)
+	}
+}
+
+// TestBufferResponseSpill 测试超过 limit 转为流式
+func TestBufferResponseSpill(t *testing.T) {
+	body := strings.Repeat("x", 1000)
+	h := BufferResponse(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 10; i++ {
+			io.WriteString(w, body[i*100:(i+1)*100])
+		}
+	}), 256)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Body.String() != body {
+		t.Fatalf("body mismatch, got %d bytes", rec.Body.Len())
+	}
+	if cl := rec.Header().Get("Content-Length"); cl != "" {
+		t.Fatalf("streamed response should not have Content-Length, got %q", cl)
+	}
+}
+
+// TestBufferResponseHead 测试 HEAD 请求没有写响应体时不设置 Content-Length: 0
+func TestBufferResponseHead(t *testing.T) {
+	h := BufferResponse(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			io.WriteString(w, "hello world")
+		}
+	}), 0)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/", nil))
+	if cl, ok := rec.Header()["Content-Length"]; ok {
+		t.Fatalf("HEAD without body should not have Content-Length, got %q", cl)
+	}
+
+	// 写了响应体的 HEAD handler 仍然按实际长度设置
+	h = BufferResponse(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello world")
+	}), 0)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/", nil))
+	if cl := rec.Header().Get("Content-Length"); cl != "11" {
+		t.Fatalf("expected Content-Length 11, got %q", cl)
+	}
+}
+
+// TestBufferResponseNoContent 测试 204 不设置 Content-Length
+func TestBufferResponseNoContent(t *testing.T) {
+	h := BufferResponse(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}), 0)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Content-Length") != "" {
+		t.Fatalf("unexpected response: %d %v", rec.Code, rec.Header())
+	}
+}
+
+// TestBufferResponsePanic 测试 handler panic 时中间件照常向上传播 panic
+func TestBufferResponsePanic(t *testing.T) {
+	srv := httptest.NewServer(BufferResponse(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "partial")
+		panic(http.ErrAbortHandler)
+	}), 0))
+	defer srv.Close()
+
+	if resp, err := http.Get(srv.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("expected aborted response")
+	}
+}
+
+// TestBufferResponseEarlyHints 测试 1xx 透传，不会吞掉最终状态码和 Content-Length
+func TestBufferResponseEarlyHints(t *testing.T) {
+	srv := httptest.NewServer(BufferResponse(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", "</style.css>; rel=preload")
+		w.WriteHeader(http.StatusEarlyHints)
+		w.WriteHeader(http.StatusAccepted)
+		io.WriteString(w, "hello")
+	}), 0))
+	defer srv.Close()
+
+	var got1xx []int
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, _ textproto.MIMEHeader) error {
+			got1xx = append(got1xx, code)
+			return nil
+		},
+	}
+	req, _ := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), "GET", srv.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if len(got1xx) != 1 || got1xx[0] != http.StatusEarlyHints {
+		t.Fatalf("expected one 103, got %v", got1xx)
+	}
+	if resp.StatusCode != http.StatusAccepted || string(body) != "hello" {
+		t.Fatalf("unexpected final response %d %q", resp.StatusCode, body)
+	}
+	if resp.ContentLength != 5 {
+		t.Fatalf("expected Content-Length 5, got %d", resp.ContentLength)
+	}
+}
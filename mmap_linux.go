@@ -0,0 +1,92 @@
+package buffer
+
+import (
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// mmapRegions 记录 mmap 池分配的所有内存块：起始地址 -> 映射长度
+// 只有登记过的切片才会被放回池或 munmap，调用方 append 超过 cap 后得到的堆切片会被识别出来，交给 GC
+type mmapRegions struct {
+	mu      sync.Mutex
+	regions map[uintptr]int
+}
+
+func (r *mmapRegions) add(b []byte) {
+	r.mu.Lock()
+	r.regions[uintptr(unsafe.Pointer(unsafe.SliceData(b)))] = cap(b)
+	r.mu.Unlock()
+}
+
+// owns 判断 b 是否是某个映射的完整视图 (起始地址和 cap 都一致)
+func (r *mmapRegions) owns(b []byte) bool {
+	if cap(b) == 0 {
+		return false
+	}
+	r.mu.Lock()
+	n, ok := r.regions[uintptr(unsafe.Pointer(unsafe.SliceData(b)))]
+	r.mu.Unlock()
+	return ok && n == cap(b)
+}
+
+func (r *mmapRegions) remove(b []byte) bool {
+	addr := uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n, ok := r.regions[addr]; !ok || n != cap(b) {
+		return false
+	}
+	delete(r.regions, addr)
+	return true
+}
+
+// NewMmapBytePool 创建堆外内存的 []byte 池 (仅 Linux)
+// 内存来自匿名 mmap，不计入 Go 堆，不会抬高 GOGC 的堆目标；丢弃时直接 munmap 归还给操作系统。
+// 校准和环形池行为与 NewBytePool 一致。SetHugePage(true) 时对每块内存 madvise(MADV_HUGEPAGE)。
+//
+// 使用约束：
+//   - 必须 Put 回 Get 得到的原始切片 (可以 [:n] 改 len，但不能改起始位置或 cap)，否则这块映射会泄漏
+//   - append 超过 cap 后得到的是普通堆切片，Put 时会被识别并交给 GC
+//   - Put 之后不能再访问，映射随时可能被 munmap，访问会直接 SIGSEGV
+//   - 池不再使用时调用 Drain，空闲的映射不在 Go 堆上，GC 不会回收
+func NewMmapBytePool(opts ...*Option) *Pool[[]byte] {
+	opt := Options().Merge(opts...)
+	hugePage := opt.HugePage != nil && *opt.HugePage
+	regions := &mmapRegions{regions: make(map[uintptr]int)}
+	pageSize := uint64(syscall.Getpagesize())
+
+	var p *Pool[[]byte]
+	p = New(
+		// make: 按页对齐向上取整后 mmap，mmap 失败时退化为堆内存
+		func(size uint64) []byte {
+			size = (max(size, 1) + pageSize - 1) / pageSize * pageSize
+			b, err := syscall.Mmap(-1, 0, int(size), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+			if err != nil {
+				return make([]byte, 0, size)
+			}
+			if hugePage {
+				_ = syscall.Madvise(b, 14) // MADV_HUGEPAGE，syscall 包没有导出该常量
+			}
+			regions.add(b)
+			return b[:0]
+		},
+		func(b []byte) []byte {
+			return b[:0]
+		},
+		// stat: 堆切片返回 cap=0，不进池
+		func(b []byte) (uint64, uint64) {
+			if !regions.owns(b) {
+				return 0, 0
+			}
+			return uint64(len(b)), uint64(cap(b))
+		},
+		opts...,
+	)
+	p.setDropFunc(func(b []byte) {
+		if regions.remove(b) {
+			_ = syscall.Munmap(b[:cap(b)])
+		}
+	})
+	return p
+}
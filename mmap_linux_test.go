@@ -0,0 +1,72 @@
+package buffer
+
+import "testing"
+
+// TestMmapBytePool 测试 mmap 池的读写与复用
+func TestMmapBytePool(t *testing.T) {
+	p := NewMmapBytePool(Options().SetHugePage(true))
+
+	b := p.Get()
+	if cap(b) < 1024 {
+		t.Fatalf("expected cap >= 1024, got %d", cap(b))
+	}
+	b = append(b, "off-heap"...)
+	p.Put(b)
+
+	b2 := p.Get()
+	if len(b2) != 0 || &b2[:1][0] != &b[:1][0] {
+		t.Fatal("expected mmap slice to be reused")
+	}
+	p.Put(b2)
+}
+
+// TestMmapBytePoolHeapSlice 测试 append 超出 cap 后的堆切片不会被放回池或 munmap
+func TestMmapBytePoolHeapSlice(t *testing.T) {
+	p := NewMmapBytePool(Options().SetCalibratedSz(4096).SetMinSize(4096))
+
+	b := p.Get()
+	orig := b
+	b = append(b, make([]byte, cap(b)+1)...) // 重新分配到堆上
+	p.Put(b)                                 // 应该被忽略
+	p.Put(orig)
+
+	if got := p.Stats().Idle; got != 1 {
+		t.Fatalf("expected only the mmap slice pooled, idle %d", got)
+	}
+}
+
+// TestMmapBytePoolDiscard 测试门卫丢弃时 munmap
+func TestMmapBytePoolDiscard(t *testing.T) {
+	p := NewMmapBytePool(Options().SetMinSize(4096).SetCalibratedSz(4096).SetMaxSize(4096))
+
+	// 造一个超大的映射：直接借用 makeFunc
+	big := p.makeFunc(1 << 20)
+	p.Put(big) // cap 远超 4096*1.5，被丢弃并 munmap
+
+	if p.Stats().Idle != 0 {
+		t.Fatal("oversized mmap slice should not be pooled")
+	}
+}
+
+// TestMmapBytePoolDrain 测试 Drain munmap 所有空闲映射
+func TestMmapBytePoolDrain(t *testing.T) {
+	p := NewMmapBytePool()
+	var unmapped int
+	onDrop := p.pool.OnDrop
+	p.pool.OnDrop = func(b []byte) {
+		unmapped++
+		onDrop(b)
+	}
+
+	p.Prewarm(5, 0)
+	if n := p.Drain(); n != 5 || unmapped != 5 {
+		t.Fatalf("expected 5 drained and unmapped, got %d/%d", n, unmapped)
+	}
+	if p.Stats().Idle != 0 {
+		t.Fatal("expected no idle mappings after Drain")
+	}
+
+	// Drain 之后仍可使用
+	b := append(p.Get(), "again"...)
+	p.Put(b)
+}
@@ -5,11 +5,21 @@ func Options() *Option {
 }
 
 type Option struct {
-	CalibratePeriod *uint64  //校准周期
-	MaxPercent      *float64 //相当于一个门卫,当cap超过CalibratedSz 就交给gc释放
-	MinSize         *uint64  //最小尺寸
-	MaxSize         *uint64  //最大尺寸
-	CalibratedSz    *uint64  //当前初始的校准尺寸
+	CalibratePeriod *uint64   //校准周期
+	MaxPercent      *float64  //相当于一个门卫,当cap超过CalibratedSz 就交给gc释放
+	MinSize         *uint64   //最小尺寸
+	MaxSize         *uint64   //最大尺寸
+	CalibratedSz    *uint64   //当前初始的校准尺寸
+	ClearOnReset    *bool     //reset 时是否 clear 元素,切片元素含指针时打开,避免池子持有引用
+	LeakCheck       *bool     //Acquire 返回的 Handle 挂 finalizer,忘记 Release 时记一次泄漏 (对象交给 GC,不归还)
+	HugePage        *bool     //mmap 池是否 madvise(MADV_HUGEPAGE),仅 NewMmapBytePool 使用
+	Align           *uint64   //校准值向上取整到 Align 的整数倍,O_DIRECT 等块设备场景使用
+	Snapshot        *Snapshot //热启动:用上次进程导出的快照初始化校准值和环形池容量
+	RingMinCap      *int      //环形池最小容量,默认 DefaultMinCapacity
+	RingMaxCap      *int      //环形池最大容量,默认 DefaultMaxCapacity
+	EmaUpFactor     *float64  //校准上涨时保留历史的比例 (0~1),越小涨得越快
+	EmaDownFactor   *float64  //校准下跌时保留历史的比例 (0~1),越大跌得越慢
+	Overflow        *bool     //环形池满时溢出到 sync.Pool 而不是丢弃,由 GC 清理
 }
 
 func (o *Option) SetCalibratePeriod(v uint64) *Option {
@@ -49,6 +59,86 @@ func (o *Option) SetCalibratedSz(v uint64) *Option {
 	return o
 }
 
+func (o *Option) SetClearOnReset(v bool) *Option {
+	if o == nil {
+		o = &Option{}
+	}
+	o.ClearOnReset = &v
+	return o
+}
+
+func (o *Option) SetLeakCheck(v bool) *Option {
+	if o == nil {
+		o = &Option{}
+	}
+	o.LeakCheck = &v
+	return o
+}
+
+func (o *Option) SetHugePage(v bool) *Option {
+	if o == nil {
+		o = &Option{}
+	}
+	o.HugePage = &v
+	return o
+}
+
+func (o *Option) SetAlign(v uint64) *Option {
+	if o == nil {
+		o = &Option{}
+	}
+	o.Align = &v
+	return o
+}
+
+func (o *Option) SetSnapshot(v Snapshot) *Option {
+	if o == nil {
+		o = &Option{}
+	}
+	o.Snapshot = &v
+	return o
+}
+
+func (o *Option) SetRingMinCap(v int) *Option {
+	if o == nil {
+		o = &Option{}
+	}
+	o.RingMinCap = &v
+	return o
+}
+
+func (o *Option) SetRingMaxCap(v int) *Option {
+	if o == nil {
+		o = &Option{}
+	}
+	o.RingMaxCap = &v
+	return o
+}
+
+func (o *Option) SetEmaUpFactor(v float64) *Option {
+	if o == nil {
+		o = &Option{}
+	}
+	o.EmaUpFactor = &v
+	return o
+}
+
+func (o *Option) SetEmaDownFactor(v float64) *Option {
+	if o == nil {
+		o = &Option{}
+	}
+	o.EmaDownFactor = &v
+	return o
+}
+
+func (o *Option) SetOverflow(v bool) *Option {
+	if o == nil {
+		o = &Option{}
+	}
+	o.Overflow = &v
+	return o
+}
+
 func (o *Option) merge(delta *Option) {
 	if delta == nil || o == nil {
 		return
@@ -68,6 +158,36 @@ func (o *Option) merge(delta *Option) {
 	if delta.CalibratedSz != nil {
 		o.CalibratedSz = delta.CalibratedSz
 	}
+	if delta.ClearOnReset != nil {
+		o.ClearOnReset = delta.ClearOnReset
+	}
+	if delta.LeakCheck != nil {
+		o.LeakCheck = delta.LeakCheck
+	}
+	if delta.HugePage != nil {
+		o.HugePage = delta.HugePage
+	}
+	if delta.Align != nil {
+		o.Align = delta.Align
+	}
+	if delta.Snapshot != nil {
+		o.Snapshot = delta.Snapshot
+	}
+	if delta.RingMinCap != nil {
+		o.RingMinCap = delta.RingMinCap
+	}
+	if delta.RingMaxCap != nil {
+		o.RingMaxCap = delta.RingMaxCap
+	}
+	if delta.EmaUpFactor != nil {
+		o.EmaUpFactor = delta.EmaUpFactor
+	}
+	if delta.EmaDownFactor != nil {
+		o.EmaDownFactor = delta.EmaDownFactor
+	}
+	if delta.Overflow != nil {
+		o.Overflow = delta.Overflow
+	}
 }
 
 func (o Option) Merge(opts ...*Option) Option {
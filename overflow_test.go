@@ -0,0 +1,105 @@
+package buffer
+
+import "testing"
+
+// TestOverflow 测试队列满时溢出到 sync.Pool，Get 先取溢出层再新建
+func TestOverflow(t *testing.T) {
+	r := NewAdaptiveRingPoolWithLimit(2, 2, func() *int { return new(int) })
+	r.Overflow = true
+
+	objs := make([]*int, 10)
+	for i := range objs {
+		objs[i] = new(int)
+	}
+	for _, o := range objs {
+		r.Put(o)
+	}
+	if r.Idle() != 2 {
+		t.Fatalf("expected 2 idle in ring, got %d", r.Idle())
+	}
+
+	for range objs {
+		r.Get()
+	}
+	ringHits, overflowHits, misses := r.HitStats()
+	if ringHits != 2 {
+		t.Fatalf("expected 2 ring hits, got %d", ringHits)
+	}
+	// race 模式下 sync.Pool 会随机丢弃，只要求命中过
+	if overflowHits == 0 {
+		t.Fatal("expected overflow hits")
+	}
+	if ringHits+overflowHits+misses != uint64(len(objs)) {
+		t.Fatalf("hits %d + %d + misses %d != %d", ringHits, overflowHits, misses, len(objs))
+	}
+}
+
+// TestOverflowWithOnDrop 测试设置了 OnDrop 时不使用溢出层
+func TestOverflowWithOnDrop(t *testing.T) {
+	var dropped int
+	r := NewAdaptiveRingPoolWithLimit(1, 1, func() *int { return new(int) })
+	r.Overflow = true
+	r.OnDrop = func(*int) { dropped++ }
+
+	r.Put(new(int))
+	r.Put(new(int))
+	r.Get()
+	r.Get()
+
+	if dropped != 1 {
+		t.Fatalf("expected 1 dropped, got %d", dropped)
+	}
+	if _, overflowHits, misses := r.HitStats(); overflowHits != 0 || misses != 1 {
+		t.Fatalf("expected no overflow hits and 1 miss, got %d %d", overflowHits, misses)
+	}
+}
+
+// TestPoolOverflowStats 测试 Option.Overflow 和 Stats 中的命中统计
+func TestPoolOverflowStats(t *testing.T) {
+	p := NewBufferPool(Options().SetOverflow(true).SetRingMinCap(1).SetRingMaxCap(1))
+
+	a, b := p.Get(), p.Get() // 2 次新建
+	p.Put(a)
+	p.Put(b) // 队列满，溢出
+	p.Get()  // 队列命中
+	p.Get()  // 溢出层命中 (race 模式下可能新建)
+
+	st := p.Stats()
+	if st.RingHits != 1 || st.OverflowHits+st.Misses != 3 {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+}
+
+// TestOverflowAfterReconfigure 测试收紧门卫后，溢出层中超限的对象不会再被借出
+func TestOverflowAfterReconfigure(t *testing.T) {
+	p := NewBufferPool(Options().SetOverflow(true).SetRingMinCap(1).SetRingMaxCap(1).SetCalibratedSz(64 << 10))
+	a, b := p.Get(), p.Get()
+	p.Put(a)
+	p.Put(b) // 溢出
+
+	if err := p.Reconfigure(Options().SetMaxSize(4096)); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if buf := p.Get(); buf.Cap() > 4096*2 {
+			t.Fatalf("got oversized buffer cap %d after Reconfigure", buf.Cap())
+		}
+	}
+}
+
+// TestOverflowShrinkBypass 测试内存压力收紧时缩容的对象不进溢出层
+func TestOverflowShrinkBypass(t *testing.T) {
+	p := NewBufferPool(Options().SetOverflow(true).SetRingMinCap(1).SetRingMaxCap(16))
+	p.Prewarm(16, 0)
+
+	m := NewPressureMonitor(p)
+	m.sample = func() float64 { return 1 }
+	m.Check()
+
+	for i := 0; i < 16; i++ {
+		p.Get()
+	}
+	if st := p.Stats(); st.OverflowHits != 0 {
+		t.Fatalf("expected shrunk buffers to be released, got %d overflow hits", st.OverflowHits)
+	}
+}
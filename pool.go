@@ -1,7 +1,9 @@
 package buffer
 
 import (
+	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // -------------------------- 核心配置（个人项目无脑用默认值，不用改） --------------------------
@@ -46,10 +48,26 @@ type AdaptiveRingPool[T any] struct {
 	_      [52]byte // 64 - 3*4 = 52
 
 	// --------------- 第五缓存行：函数指针+缓冲区（最冷字段）---------------
-	// New（函数指针，8字节） + buffer（切片，24字节） = 32字节，填充32字节占满64字节
+	// New、OnDrop（函数指针，各8字节） + buffer（切片，24字节） = 40字节，填充24字节占满64字节
 	New    func() T // 创建函数（初始化后不变）
+	OnDrop func(T)  // 队列满或缩容时被丢弃的对象回调，可为 nil（交给 GC），用于释放非 GC 管理的资源
 	buffer []T      // 环形队列（低频大尺寸访问）
-	_      [32]byte // 64 - 8 - 24 = 32
+	_      [24]byte // 64 - 8 - 8 - 24 = 24
+
+	// --------------- 第六缓存行：GC 分代（victim）模式，默认关闭 ---------------
+	// 开启后每次 GC 把队列中的空闲对象整体移入 victim，上一代 victim 丢弃
+	victim []T // 上一代空闲对象，队列为空时 Get 从这里取（受 mu 保护）
+
+	// --------------- 溢出层 + 累计统计（不随伸缩重置）---------------
+	// Overflow 为 true 时，队列已满或缩容装不下的对象放进 sync.Pool 而不是丢弃，
+	// Get 在队列和 victim 都为空时先从这里取再 New；sync.Pool 中的对象由 GC 清理，不会触发 OnDrop，
+	// 所以设置了 OnDrop 的池（如 mmap）不会使用溢出层。T 不是指针时放入 sync.Pool 有一次装箱分配。创建后、使用前设置
+	Overflow     bool
+	overflow     sync.Pool
+	admit        func(T) bool  // 溢出层取出的对象是否仍可用 (如容量没超过当前门卫)，nil 表示都可用
+	ringHits     atomic.Uint64 // 队列或 victim 命中
+	overflowHits atomic.Uint64 // 溢出层命中
+	misses       atomic.Uint64 // 调用 New 新建
 }
 
 // NewAdaptiveRingPool 创建自适应环形池，个人项目无脑用这个，默认配置足够
@@ -80,7 +98,6 @@ func (p *AdaptiveRingPool[T]) Get() T {
 	p.getCount.Add(1)
 
 	p.mu.Lock()
-	defer p.mu.Unlock()
 
 	// 2. 有空闲对象，复用，命中数+1
 	if p.count > 0 {
@@ -88,20 +105,47 @@ func (p *AdaptiveRingPool[T]) Get() T {
 		p.head = (p.head + 1) % p.curCap
 		p.count--
 		p.hitCount.Add(1)
+		p.mu.Unlock()
+		p.ringHits.Add(1)
+		return obj
+	}
+
+	// 3. 队列为空，从上一代 victim 中取
+	if n := len(p.victim); n > 0 {
+		obj := p.victim[n-1]
+		var zero T
+		p.victim[n-1] = zero
+		p.victim = p.victim[:n-1]
+		p.hitCount.Add(1)
+		p.mu.Unlock()
+		p.ringHits.Add(1)
 		return obj
 	}
+	p.mu.Unlock()
 
-	// 3. 无空闲对象，新建
+	// 4. 从溢出层取，不计入 hitCount：命中溢出层说明队列不够用
+	// 溢出层的对象可能是 Reconfigure/内存压力收紧之前放进去的，不合格的交给 GC
+	if p.Overflow {
+		if v := p.overflow.Get(); v != nil {
+			if obj := v.(T); p.admit == nil || p.admit(obj) {
+				p.overflowHits.Add(1)
+				return obj
+			}
+		}
+	}
+
+	// 5. 无空闲对象，新建 (锁外执行，New 可能很重)
+	p.misses.Add(1)
 	return p.New()
 }
 
 // Put 核心：放回对象 + 触发自动学习+伸缩逻辑，核心逻辑都在这里
 func (p *AdaptiveRingPool[T]) Put(obj T) {
 	p.mu.Lock()
-	defer p.mu.Unlock()
 
 	// 1. 队列未满，放回对象
-	if p.count < p.curCap {
+	stored := p.count < p.curCap
+	if stored {
 		p.buffer[p.tail] = obj
 		p.tail = (p.tail + 1) % p.curCap
 		p.count++
@@ -109,15 +153,35 @@ func (p *AdaptiveRingPool[T]) Put(obj T) {
 	// 队列已满，直接丢弃，避免内存溢出
 
 	// 2. 核心：自动学习+自适应伸缩，只在Put时触发，频率极低，无性能损耗
-	p.autoScale()
+	dropped := p.autoScale()
+	p.mu.Unlock()
+
+	if !stored {
+		p.drop(obj)
+	}
+	for _, d := range dropped {
+		p.drop(d)
+	}
+}
+
+// drop 处理装不下的对象：优先交给 OnDrop，其次在开启溢出层时放入 sync.Pool，否则交给 GC
+// 丢弃回调可能是系统调用 (如 munmap)，调用方应在锁外执行
+func (p *AdaptiveRingPool[T]) drop(obj T) {
+	switch {
+	case p.OnDrop != nil:
+		p.OnDrop(obj)
+	case p.Overflow:
+		p.overflow.Put(obj)
+	}
 }
 
 // autoScale 自动学习+扩容缩容核心逻辑，极简，无复杂计算，锁内执行，耗时可忽略
-func (p *AdaptiveRingPool[T]) autoScale() {
+// 返回缩容时装不下的对象，由调用方在锁外处理
+func (p *AdaptiveRingPool[T]) autoScale() []T {
 	// 总获取数为0，无需伸缩
 	total := p.getCount.Load()
 	if total == 0 {
-		return
+		return nil
 	}
 
 	// 计算命中率
@@ -127,44 +191,206 @@ func (p *AdaptiveRingPool[T]) autoScale() {
 	if hitRate > HitRateHigh && p.curCap < p.maxCap {
 		newCap := int(float64(p.curCap) * ScaleUpFactor)
 		newCap = min(newCap, p.maxCap)
-		p.resize(newCap)
-		return
+		return p.resize(newCap)
 	}
 
 	// 情况2：命中率过低 → 闲时，缩容
 	if hitRate < HitRateLow && p.curCap > p.minCap {
 		newCap := int(float64(p.curCap) * ScaleDownFactor)
 		newCap = max(newCap, p.minCap)
-		p.resize(newCap)
-		return
+		return p.resize(newCap)
 	}
 
 	// 情况3：命中率适中，不做任何操作，维持当前容量
+	return nil
 }
 
 // resize 环形队列的扩容/缩容实现，最优写法，无内存浪费，性能极致
-func (p *AdaptiveRingPool[T]) resize(newCap int) {
+// 锁内执行，返回缩容时装不下的对象，由调用方解锁后处理 (丢弃回调可能是 munmap 等系统调用)
+func (p *AdaptiveRingPool[T]) resize(newCap int) (dropped []T) {
 	if newCap == p.curCap {
-		return
+		return nil
 	}
 
 	// 新建新容量的数组
 	newBuf := make([]T, newCap)
 	// 把原队列中的空闲对象，按顺序拷贝到新数组，只拷贝有效数据，无浪费
 	copyCount := 0
-	for copyCount < p.count {
+	for copyCount < p.count && copyCount < newCap {
 		srcIdx := (p.head + copyCount) % p.curCap
 		newBuf[copyCount] = p.buffer[srcIdx]
 		copyCount++
 	}
+	// 缩容时装不下的对象交给调用方丢弃 (或放入溢出层)
+	if n := p.count - copyCount; n > 0 {
+		dropped = make([]T, 0, n)
+		for i := copyCount; i < p.count; i++ {
+			dropped = append(dropped, p.buffer[(p.head+i)%p.curCap])
+		}
+	}
 
 	// 更新队列状态，完成伸缩
 	p.buffer = newBuf
 	p.head = 0
-	p.tail = p.count
+	p.count = copyCount
+	p.tail = copyCount % newCap
 	p.curCap = newCap
 
 	// 重置统计，开始新一轮的自动学习
 	p.hitCount.Store(0)
 	p.getCount.Store(0)
+	return dropped
+}
+
+// Idle 返回当前空闲对象数
+func (p *AdaptiveRingPool[T]) Idle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.count
+}
+
+// Cap 返回当前容量
+func (p *AdaptiveRingPool[T]) Cap() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.curCap
+}
+
+// Resize 手动设置容量，按 [minCap, maxCap] 截断，装不下的空闲对象会被丢弃
+func (p *AdaptiveRingPool[T]) Resize(n int) {
+	p.resizeWith(n, p.drop)
+}
+
+// shrink 与 Resize 相同，但装不下的对象不进溢出层，用于内存压力下真正释放内存
+func (p *AdaptiveRingPool[T]) shrink(n int) {
+	p.resizeWith(n, func(obj T) {
+		if p.OnDrop != nil {
+			p.OnDrop(obj)
+		}
+	})
+}
+
+func (p *AdaptiveRingPool[T]) resizeWith(n int, drop func(T)) {
+	p.mu.Lock()
+	dropped := p.resize(max(p.minCap, min(n, p.maxCap)))
+	p.mu.Unlock()
+	for _, d := range dropped {
+		drop(d)
+	}
+}
+
+// Prewarm 预先创建 n 个空闲对象放入队列，避免启动后第一波流量全部 miss
+// n 超过当前容量时会扩容，但不超过 maxCap，返回实际放入的数量
+func (p *AdaptiveRingPool[T]) Prewarm(n int) int {
+	return p.fill(n, p.New)
+}
+
+// fill 用 mk 创建对象填充队列，创建在锁外进行 (New 可能很重)
+func (p *AdaptiveRingPool[T]) fill(n int, mk func() T) int {
+	p.mu.Lock()
+	if need := p.count + n; need > p.curCap && p.curCap < p.maxCap {
+		p.resize(min(need, p.maxCap)) // 只扩容，不会有装不下的对象
+	}
+	room := min(n, p.curCap-p.count)
+	p.mu.Unlock()
+
+	added := 0
+	for i := 0; i < room; i++ {
+		obj := mk()
+		p.mu.Lock()
+		if p.count >= p.curCap {
+			// 创建期间被并发 Put 填满
+			p.mu.Unlock()
+			p.drop(obj)
+			break
+		}
+		p.buffer[p.tail] = obj
+		p.tail = (p.tail + 1) % p.curCap
+		p.count++
+		p.mu.Unlock()
+		added++
+	}
+	return added
+}
+
+// KeepWarm 后台每隔 interval 检查一次，空闲数低于 low 时补足到 low
+// 返回的 stop 停止后台 goroutine，可重复调用
+func (p *AdaptiveRingPool[T]) KeepWarm(low int, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	var once atomic.Bool
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if idle := p.Idle(); idle < low {
+					p.Prewarm(low - idle)
+				}
+			}
+		}
+	}()
+	return func() {
+		if once.CompareAndSwap(false, true) {
+			close(done)
+		}
+	}
+}
+
+// Evict 移除 drop 返回 true 的空闲对象 (会触发 OnDrop)，返回移除的数量
+// drop 在锁内调用，OnDrop 在解锁后调用
+func (p *AdaptiveRingPool[T]) Evict(drop func(T) bool) int {
+	var evicted []T
+	p.mu.Lock()
+
+	kept := 0
+	var zero T
+	for i := 0; i < p.count; i++ {
+		idx := (p.head + i) % p.curCap
+		obj := p.buffer[idx]
+		p.buffer[idx] = zero
+		if drop(obj) {
+			evicted = append(evicted, obj)
+			continue
+		}
+		// 保留的对象按顺序紧凑到 head 之后
+		p.buffer[(p.head+kept)%p.curCap] = obj
+		kept++
+	}
+	p.count = kept
+	p.tail = (p.head + kept) % p.curCap
+
+	// victim 中的对象同样处理
+	kept = 0
+	for _, obj := range p.victim {
+		if drop(obj) {
+			evicted = append(evicted, obj)
+			continue
+		}
+		p.victim[kept] = obj
+		kept++
+	}
+	clear(p.victim[kept:])
+	p.victim = p.victim[:kept]
+	p.mu.Unlock()
+
+	if p.OnDrop != nil {
+		for _, obj := range evicted {
+			p.OnDrop(obj)
+		}
+	}
+	return len(evicted)
+}
+
+// Drain 移除所有空闲对象 (包括 victim，会触发 OnDrop)，返回移除的数量
+// 溢出层中的对象由 GC 清理，不在此列
+func (p *AdaptiveRingPool[T]) Drain() int {
+	return p.Evict(func(T) bool { return true })
+}
+
+// HitStats 返回累计的队列命中 (含 victim)、溢出层命中和新建次数
+func (p *AdaptiveRingPool[T]) HitStats() (ringHits, overflowHits, misses uint64) {
+	return p.ringHits.Load(), p.overflowHits.Load(), p.misses.Load()
 }
@@ -0,0 +1,244 @@
+package buffer
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	rtdebug "runtime/debug" // 与包内的 debug 常量区分
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// 内存压力监控
+// -----------------------------------------------------------------------------
+// 池本身不知道进程离内存上限还有多远。PressureMonitor 定期比较内存用量和上限：
+//   - Go 运行时：debug.SetMemoryLimit 设置的软上限，用量取 runtime/metrics 中
+//     /memory/classes/total:bytes 减去已归还给系统的 /memory/classes/heap/released:bytes
+//   - cgroup v2：(memory.current - memory.stat 中的 inactive_file) / memory.max，
+//     与 kubelet/cAdvisor 的 working set 一致，不把可回收的页缓存算作压力
+// 两者取较高的占比。超过 High 时收紧所有池，回落到 Low 以下时放松 (中间保持不变，防止来回抖动)。
+
+const (
+	// PressureHigh 用量占上限的比例超过它即进入压力状态
+	PressureHigh = 0.85
+	// PressureLow 压力状态下比例回落到它以下才解除
+	PressureLow = 0.7
+)
+
+// PressureTarget 可以响应内存压力的池，*Pool[T] 实现了它
+type PressureTarget interface {
+	// tighten 进入压力状态时调用，返回解除时调用的恢复函数
+	tighten() (relax func())
+}
+
+// tighten 收紧池：
+//   - maxPercent 减半逼近 1 (1.5 => 1.25)，门卫更严
+//   - 校准值减半 (不低于 MinSize)，新建对象更小，之后校准会按真实流量涨回来
+//   - 环形池缩到最小容量，多余的空闲对象直接丢弃 (不进溢出层)，并清出按新门卫会被丢弃的对象
+//
+// 恢复函数只还原 maxPercent，且仅当期间没有被 Reconfigure 改动过
+func (p *Pool[T]) tighten() (relax func()) {
+	old := p.cfg.Load().maxPercent
+	tight := 1 + (old-1)/2
+	_ = p.Reconfigure(Options().
+		SetMaxPercent(tight).
+		SetCalibratedSz(p.CalibratedSize() / 2)) // maxPercent >= 1，不会失败；校准值由 clamp 兜底
+	p.pool.shrink(0) // 不能进溢出层，否则内存并没有释放
+	return func() {
+		if p.cfg.Load().maxPercent == tight {
+			_ = p.Reconfigure(Options().SetMaxPercent(old))
+		}
+	}
+}
+
+// PressureMonitor 内存压力监控器
+type PressureMonitor struct {
+	High float64 // 进入压力状态的阈值，默认 PressureHigh，Start 前设置
+	Low  float64 // 解除压力状态的阈值，默认 PressureLow，Start 前设置
+
+	sample func() float64 // 采样函数，返回用量占上限的比例，没有上限时返回 0
+
+	mu      sync.Mutex
+	targets []PressureTarget
+	relaxes []func() // 压力状态下各池的恢复函数，nil 表示不在压力状态
+	under   atomic.Bool
+	ratio   atomic.Uint64 // 最近一次采样的比例 (math.Float64bits)
+}
+
+// NewPressureMonitor 创建监控 targets 的内存压力监控器，需要调用 Start 或 Check 才会生效
+func NewPressureMonitor(targets ...PressureTarget) *PressureMonitor {
+	return &PressureMonitor{
+		High:    PressureHigh,
+		Low:     PressureLow,
+		sample:  memoryPressure,
+		targets: targets,
+	}
+}
+
+// Add 加入新的池，当前处于压力状态时立即收紧它
+func (m *PressureMonitor) Add(t PressureTarget) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.targets = append(m.targets, t)
+	if m.relaxes != nil {
+		m.relaxes = append(m.relaxes, t.tighten())
+	}
+}
+
+// Check 采样一次并按需收紧或放松，返回是否处于压力状态
+func (m *PressureMonitor) Check() bool {
+	ratio := m.sample()
+	m.ratio.Store(math.Float64bits(ratio))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case m.relaxes == nil && ratio >= m.High:
+		m.relaxes = make([]func(), 0, len(m.targets))
+		for _, t := range m.targets {
+			m.relaxes = append(m.relaxes, t.tighten())
+		}
+	case m.relaxes != nil && ratio < m.Low:
+		for _, relax := range m.relaxes {
+			relax()
+		}
+		m.relaxes = nil
+	}
+	m.under.Store(m.relaxes != nil)
+	return m.relaxes != nil
+}
+
+// Pressure 返回最近一次采样的比例和是否处于压力状态
+func (m *PressureMonitor) Pressure() (ratio float64, under bool) {
+	return math.Float64frombits(m.ratio.Load()), m.under.Load()
+}
+
+// Start 后台每隔 interval 调用一次 Check，返回的 stop 停止后台 goroutine，可重复调用
+// stop 不会自动放松，进程仍处于压力状态时池保持收紧
+func (m *PressureMonitor) Start(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	var once atomic.Bool
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				m.Check()
+			}
+		}
+	}()
+	return func() {
+		if once.CompareAndSwap(false, true) {
+			close(done)
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
+// 采样
+// -----------------------------------------------------------------------------
+
+// memoryPressure Go 软上限和 cgroup 上限中占比较高的一个，都没有设置时返回 0
+func memoryPressure() float64 {
+	return max(goLimitRatio(), cgroupRatio(cgroupDir()))
+}
+
+// goLimitRatio Go 运行时用量 / debug.SetMemoryLimit，未设置上限时返回 0
+func goLimitRatio() float64 {
+	limit := rtdebug.SetMemoryLimit(-1) // 负数只读取不修改
+	if limit <= 0 || limit == math.MaxInt64 {
+		return 0
+	}
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+	for _, s := range samples {
+		if s.Value.Kind() != metrics.KindUint64 {
+			return 0
+		}
+	}
+	used := samples[0].Value.Uint64() - samples[1].Value.Uint64()
+	return float64(used) / float64(limit)
+}
+
+// cgroupDir 当前进程所在的 cgroup v2 目录，不是 cgroup v2 时返回空串
+func cgroupDir() string {
+	f, err := os.Open("/proc/self/cgroup")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// cgroup v2 只有一行 "0::/path"
+		if path, ok := strings.CutPrefix(s.Text(), "0::"); ok {
+			return filepath.Join("/sys/fs/cgroup", path)
+		}
+	}
+	return ""
+}
+
+// cgroupRatio working set / memory.max，没有上限 ("max") 或读取失败时返回 0
+// working set = memory.current - inactive_file，I/O 多的容器页缓存会把 memory.current 顶到接近上限，
+// 但这部分内核随时可以回收，不应该让池一直处于收紧状态
+func cgroupRatio(dir string) float64 {
+	if dir == "" {
+		return 0
+	}
+	limit, err := readCgroupUint(filepath.Join(dir, "memory.max"))
+	if err != nil || limit == 0 {
+		return 0
+	}
+	used, err := readCgroupUint(filepath.Join(dir, "memory.current"))
+	if err != nil {
+		return 0
+	}
+	// memory.stat 读取失败时按 0 处理，退化为 memory.current
+	if inactive, err := readCgroupStat(filepath.Join(dir, "memory.stat"), "inactive_file"); err == nil {
+		used -= min(inactive, used)
+	}
+	return float64(used) / float64(limit)
+}
+
+// readCgroupStat 读取 memory.stat 这类 "key value" 每行一项的文件中 key 的值
+func readCgroupStat(path, key string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if v, ok := strings.CutPrefix(s.Text(), key+" "); ok {
+			return strconv.ParseUint(strings.TrimSpace(v), 10, 64)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
+	return 0, os.ErrNotExist
+}
+
+// readCgroupUint 读取只有一个数字的 cgroup 文件，"max" 视为没有上限返回 0
+func readCgroupUint(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(string(data))
+	if s == "max" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
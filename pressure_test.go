@@ -0,0 +1,110 @@
+package buffer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestPressureMonitor 测试超过 High 收紧，回落到 Low 以下放松，中间保持
+func TestPressureMonitor(t *testing.T) {
+	p := NewBufferPool(Options().SetCalibratedSz(8192))
+	objs := NewObjectPool(func() *int { return new(int) }, func(v *int) *int { return v })
+	p.Prewarm(100, 0)
+	objs.pool.Prewarm(100)
+
+	ratio := 0.5
+	m := NewPressureMonitor(p, objs)
+	m.sample = func() float64 { return ratio }
+
+	if m.Check() {
+		t.Fatal("0.5 should not be under pressure")
+	}
+
+	ratio = 0.9
+	if !m.Check() {
+		t.Fatal("0.9 should be under pressure")
+	}
+	if sz := p.CalibratedSize(); sz != 4096 {
+		t.Fatalf("expected calibrated size halved to 4096, got %d", sz)
+	}
+	if mp := p.cfg.Load().maxPercent; mp != 1.25 {
+		t.Fatalf("expected maxPercent 1.25, got %v", mp)
+	}
+	if st := p.Stats(); st.RingCap != DefaultMinCapacity || st.Idle > DefaultMinCapacity {
+		t.Fatalf("expected ring shrunk to min: %+v", st)
+	}
+	if st := objs.Stats(); st.RingCap != DefaultMinCapacity {
+		t.Fatalf("expected object ring shrunk to min: %+v", st)
+	}
+
+	// 处于 Low 和 High 之间，保持收紧
+	ratio = 0.8
+	if !m.Check() {
+		t.Fatal("0.8 should stay under pressure")
+	}
+
+	ratio = 0.6
+	if m.Check() {
+		t.Fatal("0.6 should clear the pressure")
+	}
+	if mp := p.cfg.Load().maxPercent; mp != 1.5 {
+		t.Fatalf("expected maxPercent restored to 1.5, got %v", mp)
+	}
+	if got, under := m.Pressure(); got != 0.6 || under {
+		t.Fatalf("unexpected Pressure() %v %v", got, under)
+	}
+}
+
+// TestPressureRelaxAfterReconfigure 测试压力期间被 Reconfigure 过的配置不会被覆盖
+func TestPressureRelaxAfterReconfigure(t *testing.T) {
+	p := NewBufferPool()
+	ratio := 0.9
+	m := NewPressureMonitor(p)
+	m.sample = func() float64 { return ratio }
+	m.Check()
+
+	if err := p.Reconfigure(Options().SetMaxPercent(3)); err != nil {
+		t.Fatal(err)
+	}
+	ratio = 0
+	m.Check()
+	if mp := p.cfg.Load().maxPercent; mp != 3 {
+		t.Fatalf("expected maxPercent 3 kept, got %v", mp)
+	}
+}
+
+// TestCgroupRatio 测试读取 cgroup v2 文件
+func TestCgroupRatio(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, v string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(v), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("memory.max", "1000\n")
+	write("memory.current", "900\n")
+	if r := cgroupRatio(dir); r != 0.9 {
+		t.Fatalf("expected 0.9, got %v", r)
+	}
+
+	// 可回收的页缓存不算压力
+	write("memory.stat", "anon 300\nfile 600\nactive_file 100\ninactive_file 500\n")
+	if r := cgroupRatio(dir); r != 0.4 {
+		t.Fatalf("expected 0.4 without inactive_file, got %v", r)
+	}
+
+	write("memory.max", "max\n")
+	if r := cgroupRatio(dir); r != 0 {
+		t.Fatalf("expected 0 without limit, got %v", r)
+	}
+	if r := cgroupRatio(filepath.Join(dir, "missing")); r != 0 {
+		t.Fatalf("expected 0 for missing dir, got %v", r)
+	}
+
+	// 真实环境采样不应出错，没有上限时为 0
+	if r := memoryPressure(); r < 0 {
+		t.Fatalf("unexpected pressure %v", r)
+	}
+}
@@ -0,0 +1,22 @@
+package buffer
+
+import "time"
+
+// Prewarm 预先创建 n 个尺寸为 size 的对象放入池中，返回实际放入的数量
+// size 为 0 时使用当前校准值，否则按 [MinSize, MaxSize] 截断并对齐；数量受环形池 maxCap 限制
+func (p *Pool[T]) Prewarm(n int, size uint64) int {
+	if size == 0 {
+		size = p.CalibratedSize()
+	} else {
+		size = p.cfg.Load().clamp(size)
+	}
+	return p.pool.fill(n, func() T {
+		return p.makeFunc(size)
+	})
+}
+
+// KeepWarm 后台保持空闲对象数不低于 low，补充的对象使用当时的校准值
+// 返回的 stop 停止后台 goroutine
+func (p *Pool[T]) KeepWarm(low int, interval time.Duration) (stop func()) {
+	return p.pool.KeepWarm(low, interval)
+}
@@ -0,0 +1,53 @@
+package buffer
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPrewarm 测试预热后第一波 Get 全部命中
+func TestPrewarm(t *testing.T) {
+	p := NewBufferPool()
+
+	if n := p.Prewarm(100, 4096); n != 100 {
+		t.Fatalf("expected 100 prewarmed, got %d", n)
+	}
+	if st := p.Stats(); st.Idle != 100 || st.RingCap < 100 {
+		t.Fatalf("unexpected stats after prewarm: %+v", st)
+	}
+
+	buf := p.Get()
+	if buf.Cap() < 4096 {
+		t.Fatalf("expected prewarmed buffer cap >= 4096, got %d", buf.Cap())
+	}
+	p.Put(buf)
+}
+
+// TestPrewarmMaxCap 测试预热不超过 maxCap
+func TestPrewarmMaxCap(t *testing.T) {
+	r := NewAdaptiveRingPoolWithLimit(4, 16, func() []byte { return make([]byte, 8) })
+
+	if n := r.Prewarm(100); n != 16 {
+		t.Fatalf("expected 16 prewarmed, got %d", n)
+	}
+	if r.Cap() != 16 || r.Idle() != 16 {
+		t.Fatalf("unexpected cap %d idle %d", r.Cap(), r.Idle())
+	}
+}
+
+// TestKeepWarm 测试后台补足空闲对象
+func TestKeepWarm(t *testing.T) {
+	p := NewBufferPool()
+	stop := p.KeepWarm(10, time.Millisecond)
+	defer stop()
+
+	deadline := time.Now().Add(time.Second)
+	for p.Stats().Idle < 10 {
+		if time.Now().After(deadline) {
+			t.Fatalf("idle count stayed at %d", p.Stats().Idle)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+	stop() // 重复调用应该安全
+}
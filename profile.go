@@ -0,0 +1,51 @@
+package buffer
+
+// -----------------------------------------------------------------------------
+// 预设调优档位
+// -----------------------------------------------------------------------------
+// 各参数互相牵制，单独调一个往往适得其反：
+//   - MaxPercent 越小，门卫越严，大 buffer 越早被丢弃，内存越省，但重新分配越多
+//   - EmaUpFactor 越小，水位涨得越快；EmaDownFactor 越大，水位跌得越慢
+//   - CalibratePeriod 越小，对流量变化反应越快，但容易被短时抖动带偏
+//   - RingMinCap/RingMaxCap 决定最多囤多少个空闲对象
+// 档位只设置这些“策略”字段，尺寸 (MinSize/MaxSize/CalibratedSz) 仍由业务决定，
+// 可以与其它 Option 一起传入，后面的覆盖前面的：
+//
+//	p := buffer.NewBufferPool(buffer.ProfileLowMemory(), buffer.Options().SetMaxSize(4<<20))
+//
+// 每次调用都返回新的 *Option，修改它不会影响其它调用方
+
+// ProfileLowMemory 省内存：门卫严、水位涨得慢跌得快、空闲对象少
+// 适合内存受限的容器或 sidecar，代价是流量回升时需要重新分配
+func ProfileLowMemory() *Option {
+	return Options().
+		SetMaxPercent(1.2).
+		SetEmaUpFactor(0.6).
+		SetEmaDownFactor(0.5).
+		SetCalibratePeriod(1000).
+		SetRingMinCap(4).
+		SetRingMaxCap(64)
+}
+
+// ProfileBalanced 均衡：即默认配置，显式写出便于在配置中引用
+func ProfileBalanced() *Option {
+	return Options().
+		SetMaxPercent(1.5).
+		SetEmaUpFactor(emaUpFactor).
+		SetEmaDownFactor(emaDownFactor).
+		SetCalibratePeriod(1000).
+		SetRingMinCap(DefaultMinCapacity).
+		SetRingMaxCap(DefaultMaxCapacity)
+}
+
+// ProfileThroughput 高吞吐：门卫宽、水位涨得快跌得慢、空闲对象多
+// 流量回落后仍保留较大的 buffer，突发流量回来时几乎不需要重新分配，代价是常驻内存更高
+func ProfileThroughput() *Option {
+	return Options().
+		SetMaxPercent(2.0).
+		SetEmaUpFactor(0.2).
+		SetEmaDownFactor(0.95).
+		SetCalibratePeriod(5000).
+		SetRingMinCap(64).
+		SetRingMaxCap(4096)
+}
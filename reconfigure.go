@@ -0,0 +1,42 @@
+package buffer
+
+// Reconfigure 在运行中修改 MinSize/MaxSize/CalibratePeriod/MaxPercent/EmaUpFactor/EmaDownFactor/CalibratedSz，流量不中断
+// 只有传入的字段会变化；其余 Option (Align、ClearOnReset、LeakCheck 等) 只在创建时生效，这里忽略。
+// Align 不可修改：对齐池的 stat 函数按创建时的 align 判断，改了之后新建的 buffer 会全部被拒绝。
+// 合并后的配置非法时返回 Option.Validate 的错误，配置保持不变。
+// 生效后校准值按新的上下限截断，环形池中按新门卫判决会被丢弃的空闲对象立即清出。
+// 与并发的 calibrate 存在竞争，最坏情况下校准值在下一个校准周期才完全落入新的范围。
+func (p *Pool[T]) Reconfigure(opts ...*Option) error {
+	var cfg *calibConfig
+	for {
+		old := p.cfg.Load()
+		opt := Options().Merge(append([]*Option{old.options()}, opts...)...)
+		opt.SetAlign(old.align)
+		if err := opt.Validate(); err != nil {
+			return err
+		}
+		cfg = newCalibConfig(opt)
+		if p.cfg.CompareAndSwap(old, cfg) {
+			break
+		}
+	}
+
+	// 截断校准值，显式指定 CalibratedSz 时以它为准
+	sz := p.calibratedSz.Load()
+	if opt := Options().Merge(opts...); opt.CalibratedSz != nil {
+		sz = *opt.CalibratedSz
+	}
+	sz = cfg.clamp(sz)
+	p.calibratedSz.Store(sz)
+
+	// 对象池模式没有尺寸，不需要清理
+	if p.statFunc == nil {
+		return nil
+	}
+	limit := uint64(float64(sz) * cfg.maxPercent)
+	p.pool.Evict(func(v T) bool {
+		_, capVal := p.statFunc(v)
+		return capVal > limit
+	})
+	return nil
+}
@@ -0,0 +1,101 @@
+package buffer
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestReconfigureShrink 测试缩小 MaxSize 后校准值被截断，超限的空闲对象被清出
+func TestReconfigureShrink(t *testing.T) {
+	p := NewBufferPool(Options().SetCalibratedSz(64 << 10))
+	p.Prewarm(10, 64<<10)
+
+	if err := p.Reconfigure(Options().SetMaxSize(4096)); err != nil {
+		t.Fatal(err)
+	}
+	st := p.Stats()
+	if st.CalibratedSize != 4096 {
+		t.Errorf("expected calibrated size 4096, got %d", st.CalibratedSize)
+	}
+	if st.Idle != 0 {
+		t.Errorf("expected oversized buffers evicted, idle %d", st.Idle)
+	}
+
+	buf := p.Get()
+	if buf.Cap() > 4096 {
+		t.Errorf("expected new buffer within MaxSize, got cap %d", buf.Cap())
+	}
+	p.Put(buf)
+}
+
+// TestReconfigureKeepsOtherFields 测试只修改传入的字段
+func TestReconfigureKeepsOtherFields(t *testing.T) {
+	p := NewBufferPool(Options().SetMinSize(2048).SetMaxPercent(3))
+	if err := p.Reconfigure(Options().SetCalibratePeriod(10)); err != nil {
+		t.Fatal(err)
+	}
+	cfg := p.cfg.Load()
+	if cfg.minSize != 2048 || cfg.maxPercent != 3 || cfg.calibratePeriod != 10 {
+		t.Fatalf("unexpected config %+v", *cfg)
+	}
+
+	if err := p.Reconfigure(Options().SetMinSize(1 << 30)); err == nil {
+		t.Fatal("expected error for MinSize > MaxSize")
+	}
+	if p.cfg.Load() != cfg {
+		t.Fatal("invalid reconfigure should not change config")
+	}
+}
+
+// TestReconfigureConcurrent 测试流量中修改配置
+func TestReconfigureConcurrent(t *testing.T) {
+	p := NewBufferPool(Options().SetCalibratePeriod(10))
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				buf := p.Get()
+				buf.Write(make([]byte, 3000))
+				p.Put(buf)
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		p.Reconfigure(Options().SetMaxSize(uint64(2048 + i*100)).SetMaxPercent(1.2 + float64(i%3)/10))
+	}
+	close(stop)
+	wg.Wait()
+
+	if sz := p.CalibratedSize(); sz > 2048+99*100 {
+		t.Errorf("calibrated size %d exceeds final MaxSize", sz)
+	}
+}
+
+// TestRingDropOutsideLock 测试缩容和 Evict 在解锁后才调用 OnDrop (回调里再访问池不会死锁)
+func TestRingDropOutsideLock(t *testing.T) {
+	r := NewAdaptiveRingPoolWithLimit(1, 16, func() *int { return new(int) })
+	var dropped int
+	r.OnDrop = func(*int) {
+		r.Idle() // 锁内调用会在自旋锁上死锁
+		dropped++
+	}
+
+	r.Prewarm(8)
+	r.Resize(2)
+	if dropped != 6 {
+		t.Fatalf("expected 6 dropped by Resize, got %d", dropped)
+	}
+	if n := r.Evict(func(*int) bool { return true }); n != 2 || dropped != 8 {
+		t.Fatalf("expected 2 evicted, got %d (dropped %d)", n, dropped)
+	}
+}
@@ -0,0 +1,92 @@
+package buffer
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Scope 请求级借用范围：通过它借出的对象在 Close 时统一归还给各自的池
+// 借出的是 *Handle，[]byte 等切片 append 后要用 Set 写回，Close 才能归还新的底层数组并让校准看到真实用量
+// 典型用法：
+//
+//	s := NewScope()
+//	defer s.Close() // handler panic 时同样会执行
+//	buf := buffer.Get(s, bufPool).Value()
+//	h := buffer.Get(s, bytePool)
+//	h.Set(append(h.Value(), data...))
+//
+// Scope 不是并发安全的，一个请求 (goroutine) 一个 Scope
+type Scope struct {
+	releases []func()
+}
+
+// NewScope 创建借用范围
+func NewScope() *Scope {
+	return &Scope{}
+}
+
+// GetScoped 从池中借出对象，并登记到 s，s.Close 时自动 Release
+// 登记后不要再手动 Put Value()，否则 Close 时会重复归还；-tags buffer_debug 下会直接 panic
+// 提前 Release 是安全的，Close 时不会重复归还
+func (p *Pool[T]) GetScoped(s *Scope) *Handle[T] {
+	h := p.Acquire()
+	h.scoped = true
+	if debug {
+		trackScoped(h.val)
+	}
+	s.releases = append(s.releases, h.Release)
+	return h
+}
+
+// Get 即 p.GetScoped(s)
+// Go 的方法不能有类型参数，Scope 无法提供 s.Get(pool)，所以写成包级函数 buffer.Get(s, pool)
+func Get[T any](s *Scope, p *Pool[T]) *Handle[T] {
+	return p.GetScoped(s)
+}
+
+// Close 按借出的逆序归还所有对象，可重复调用，Close 之后 Scope 可以继续使用
+func (s *Scope) Close() {
+	for i := len(s.releases) - 1; i >= 0; i-- {
+		s.releases[i]()
+		s.releases[i] = nil
+	}
+	s.releases = s.releases[:0]
+}
+
+// -----------------------------------------------------------------------------
+// debug：检测被 Scope 登记的对象又被手动 Put
+// -----------------------------------------------------------------------------
+
+// scopedObjects 记录所有 Scope 借出未归还的对象，key 为对象底层地址
+var scopedObjects sync.Map
+
+// objectID 取引用类型的底层地址作为对象标识，值类型无法追踪返回 0
+func objectID(v any) uintptr {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return rv.Pointer()
+	}
+	return 0
+}
+
+func trackScoped(v any) {
+	if id := objectID(v); id != 0 {
+		scopedObjects.Store(id, struct{}{})
+	}
+}
+
+func untrackScoped(v any) {
+	if id := objectID(v); id != 0 {
+		scopedObjects.Delete(id)
+	}
+}
+
+// checkScopedPut 在 debug 构建的 Pool.Put 中调用
+func checkScopedPut(v any) {
+	if id := objectID(v); id != 0 {
+		if _, ok := scopedObjects.Load(id); ok {
+			panic("buffer: Put of an object still tracked by a Scope")
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package buffer
+
+import "testing"
+
+// TestScopeClose 测试 Close 归还所有借出的对象
+func TestScopeClose(t *testing.T) {
+	bufPool := NewBufferPool()
+	bytePool := NewBytePool()
+
+	s := NewScope()
+	buf := Get(s, bufPool).Value()
+	h := bytePool.GetScoped(s)
+	buf.WriteString("scoped")
+	h.Set(append(h.Value(), "scoped"...))
+	s.Close()
+	s.Close() // 重复调用应该安全
+
+	if bufPool.Stats().Idle != 1 || bytePool.Stats().Idle != 1 {
+		t.Fatalf("expected objects returned, idle %d/%d", bufPool.Stats().Idle, bytePool.Stats().Idle)
+	}
+	if buf.Len() != 0 {
+		t.Fatal("expected buffer reset on Close")
+	}
+}
+
+// TestScopeSliceCalibration 测试 append 后 Set 写回，Close 归还新的底层数组，校准能看到真实用量
+func TestScopeSliceCalibration(t *testing.T) {
+	p := NewBytePool(Options().SetCalibratePeriod(10))
+	chunk := make([]byte, 8000)
+
+	for i := 0; i < 100; i++ {
+		s := NewScope()
+		h := Get(s, p)
+		h.Set(append(h.Value(), chunk...))
+		s.Close()
+	}
+
+	if sz := p.CalibratedSize(); sz < 8000 {
+		t.Fatalf("expected calibrated size >= 8000, got %d", sz)
+	}
+}
+
+// TestScopeEarlyRelease 测试提前 Release 后 Close 不会重复归还
+func TestScopeEarlyRelease(t *testing.T) {
+	p := NewBufferPool()
+	s := NewScope()
+	h := p.GetScoped(s)
+	h.Release()
+	s.Close()
+
+	if p.Stats().Idle != 1 {
+		t.Fatalf("expected exactly 1 idle, got %d", p.Stats().Idle)
+	}
+}
+
+// TestScopePanic 测试 panic 时通过 defer 归还
+func TestScopePanic(t *testing.T) {
+	p := NewBufferPool()
+
+	func() {
+		defer func() { recover() }()
+		s := NewScope()
+		defer s.Close()
+		p.GetScoped(s).Value().WriteString("x")
+		panic("handler failed")
+	}()
+
+	if p.Stats().Idle != 1 {
+		t.Fatalf("expected buffer returned after panic, idle %d", p.Stats().Idle)
+	}
+}
+
+// TestScopeDoublePut 测试 debug 构建下检测手动 Put
+func TestScopeDoublePut(t *testing.T) {
+	if !debug {
+		t.Skip("requires -tags buffer_debug")
+	}
+	p := NewBufferPool()
+	s := NewScope()
+	buf := p.GetScoped(s).Value()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic on Put of scoped object")
+			}
+		}()
+		p.Put(buf)
+	}()
+
+	s.Close()
+	p.Put(p.Get()) // Close 之后不再追踪
+}
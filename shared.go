@@ -0,0 +1,58 @@
+package buffer
+
+import "sync/atomic"
+
+// Shared 引用计数的共享对象，适用于一份编码结果扇出给多个连接的场景
+// 最后一个 Release 的持有者负责把对象归还给来源池
+type Shared[T any] struct {
+	val  T
+	pool *Pool[T]
+	refs atomic.Int32
+}
+
+// Share 把已填充好数据的 v 包装成共享对象，初始引用计数为 1
+func (p *Pool[T]) Share(v T) *Shared[T] {
+	s := &Shared[T]{val: v, pool: p}
+	s.refs.Store(1)
+	return s
+}
+
+// GetShared 从池中取一个对象并包装成共享对象
+func (p *Pool[T]) GetShared() *Shared[T] {
+	return p.Share(p.Get())
+}
+
+// Value 返回共享的对象，引用计数归零后不能再访问
+func (s *Shared[T]) Value() T {
+	return s.val
+}
+
+// Retain 引用计数 +1，交给新的持有者前调用
+func (s *Shared[T]) Retain() *Shared[T] {
+	n := s.refs.Add(1)
+	if debug && n <= 1 {
+		panic("buffer: Shared.Retain after final Release")
+	}
+	return s
+}
+
+// Release 引用计数 -1，归零时归还给来源池
+func (s *Shared[T]) Release() {
+	n := s.refs.Add(-1)
+	if n > 0 {
+		return
+	}
+	if n < 0 {
+		if debug {
+			panic("buffer: Shared.Release called more times than Retain")
+		}
+		return
+	}
+	v := s.val
+	if debug {
+		// 归零后清空，之后的 Value 访问拿到零值，便于暴露 use-after-release
+		var zero T
+		s.val = zero
+	}
+	s.pool.Put(v)
+}
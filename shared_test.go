@@ -0,0 +1,53 @@
+package buffer
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestSharedRelease 测试最后一个 Release 才归还
+func TestSharedRelease(t *testing.T) {
+	p := NewBytePool()
+
+	b := p.Get()
+	b = append(b, "message"...)
+	s := p.Share(b)
+
+	const writers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		s.Retain()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer s.Release()
+			if string(s.Value()) != "message" {
+				t.Error("unexpected shared content")
+			}
+		}()
+	}
+	s.Release() // 创建者释放自己的引用
+	wg.Wait()
+
+	if n := s.refs.Load(); n != 0 {
+		t.Fatalf("expected refs 0, got %d", n)
+	}
+}
+
+// TestSharedOverRelease 测试多次 Release 的误用检测
+func TestSharedOverRelease(t *testing.T) {
+	p := NewBufferPool()
+	s := p.GetShared()
+	s.Release()
+
+	defer func() {
+		r := recover()
+		if debug && r == nil {
+			t.Fatal("expected panic in debug build")
+		}
+		if !debug && r != nil {
+			t.Fatalf("unexpected panic in release build: %v", r)
+		}
+	}()
+	s.Release()
+}
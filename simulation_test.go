@@ -115,3 +115,89 @@ func TestCooldownDebug(t *testing.T) {
 		}
 	}
 }
+
+// profileRun 一个档位在同一段流量下的表现
+type profileRun struct {
+	name       string
+	cooldownSz uint64 // 流量回落后的校准值，新建 buffer 的尺寸
+	resident   uint64 // 流量回落后池中空闲 buffer 的容量总和，即常驻内存
+	regrows    int    // 流量回升阶段 buffer 容量不够需要扩容的次数，代表重新分配
+}
+
+// runProfile 依次跑 突增(10KB) -> 回落(2KB) -> 再突增(10KB)，每轮同时借出 batch 个 buffer
+func runProfile(name string, opt *Option) profileRun {
+	p := NewBufferPool(opt)
+	rnd := rand.New(rand.NewSource(1))
+	batch := make([]*bytes.Buffer, 8)
+	phase := func(rounds, base, jitter int) (regrows int) {
+		for i := 0; i < rounds; i++ {
+			for j := range batch {
+				buf := p.Get()
+				usage := base + rnd.Intn(jitter*2) - jitter
+				if buf.Cap() < usage {
+					regrows++
+				}
+				buf.Write(make([]byte, usage))
+				batch[j] = buf
+			}
+			for _, buf := range batch {
+				p.Put(buf)
+			}
+		}
+		return regrows
+	}
+
+	phase(2000, 10240, 500)
+	phase(2000, 2048, 200)
+	run := profileRun{name: name, cooldownSz: p.CalibratedSize()}
+	p.pool.Evict(func(buf *bytes.Buffer) bool {
+		run.resident += uint64(buf.Cap())
+		return false // 只统计，不移除
+	})
+	run.regrows = phase(1000, 10240, 500)
+	return run
+}
+
+// TestProfiles 同一段流量下对比三个档位，越省内存的档位再突增时扩容越多：
+//   - 回落后的校准值和常驻内存 LowMemory < Balanced < Throughput
+//   - 再突增时的扩容次数 LowMemory > Balanced > Throughput
+func TestProfiles(t *testing.T) {
+	runs := []profileRun{
+		runProfile("LowMemory", ProfileLowMemory()),
+		runProfile("Balanced", ProfileBalanced()),
+		runProfile("Throughput", ProfileThroughput()),
+	}
+
+	fmt.Println("Profile    | CooldownSz | Resident | Regrows")
+	fmt.Println("-----------|------------|----------|--------")
+	for _, r := range runs {
+		fmt.Printf("%-10s | %8dB  | %7dB | %d\n", r.name, r.cooldownSz, r.resident, r.regrows)
+	}
+
+	for i := 1; i < len(runs); i++ {
+		prev, cur := runs[i-1], runs[i]
+		if prev.cooldownSz >= cur.cooldownSz {
+			t.Errorf("%s cooldown size %d should be below %s %d", prev.name, prev.cooldownSz, cur.name, cur.cooldownSz)
+		}
+		if prev.resident >= cur.resident {
+			t.Errorf("%s resident %d should be below %s %d", prev.name, prev.resident, cur.name, cur.resident)
+		}
+		if prev.regrows <= cur.regrows {
+			t.Errorf("%s regrows %d should exceed %s %d", prev.name, prev.regrows, cur.name, cur.regrows)
+		}
+	}
+}
+
+func TestProfileBalancedIsDefault(t *testing.T) {
+	def := defaultOptions()
+	bal := defaultOptions(ProfileBalanced())
+	if *def.MaxPercent != *bal.MaxPercent || *def.CalibratePeriod != *bal.CalibratePeriod ||
+		*def.EmaUpFactor != *bal.EmaUpFactor || *def.EmaDownFactor != *bal.EmaDownFactor {
+		t.Fatal("ProfileBalanced should match the defaults")
+	}
+	for _, opt := range []*Option{ProfileLowMemory(), ProfileBalanced(), ProfileThroughput()} {
+		if err := opt.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package buffer
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestJSONLogHandler 测试输出与 slog.JSONHandler 一致
+func TestJSONLogHandler(t *testing.T) {
+	var got, want bytes.Buffer
+	opts := &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{} // 去掉时间，便于比较
+			}
+			return a
+		},
+	}
+
+	l1 := slog.New(NewJSONLogHandler(&got, opts)).With("svc", "api").WithGroup("req")
+	l2 := slog.New(slog.NewJSONHandler(&want, opts)).With("svc", "api").WithGroup("req")
+	l1.Info("hello", "id", 1)
+	l2.Info("hello", "id", 1)
+	l1.Debug("filtered")
+
+	if got.String() != want.String() {
+		t.Fatalf("got %s, want %s", got.String(), want.String())
+	}
+}
+
+// TestTextLogHandlerConcurrent 测试并发写入不交错，且池统计可用
+func TestTextLogHandlerConcurrent(t *testing.T) {
+	var out bytes.Buffer
+	h := NewTextLogHandler(&out, nil, Options().SetCalibratePeriod(100))
+	logger := slog.New(h)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				logger.Info("request done", "worker", id, "payload", strings.Repeat("x", 600))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1600 {
+		t.Fatalf("expected 1600 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "time=") {
+			t.Fatalf("interleaved line: %q", line)
+		}
+	}
+
+	st := h.Stats()
+	t.Logf("log pool stats: %+v", st)
+	if st.CalibratedSize < uint64(len(lines[0])) {
+		t.Errorf("calibrated size %d smaller than a log line %d", st.CalibratedSize, len(lines[0]))
+	}
+}
+
+// BenchmarkJSONLogHandler 与 slog.NewJSONHandler 对比，派生 logger 不应在每条记录上额外分配
+// Parallel 用于确认渲染不在锁内串行
+func BenchmarkJSONLogHandler(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		h    slog.Handler
+	}{
+		{"Pooled", NewJSONLogHandler(io.Discard, nil)},
+		{"Stdlib", slog.NewJSONHandler(io.Discard, nil)},
+	} {
+		logger := slog.New(bc.h).With("svc", "api", "version", 3).WithGroup("req")
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logger.Info("request done", "id", i, "path", "/v1/items")
+			}
+		})
+		b.Run(bc.name+"/Parallel", func(b *testing.B) {
+			b.ReportAllocs()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					logger.Info("request done", "id", i, "path", "/v1/items")
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package buffer
+
+// Snapshot 池学到的状态，用于进程重启后热启动，跳过 calibrate/autoScale 的收敛过程
+// 字段带 json tag，直接 json.Marshal/Unmarshal 即可持久化
+type Snapshot struct {
+	CalibratedSize uint64 `json:"calibrated_size"` // 校准值
+	RingCap        int    `json:"ring_cap"`        // 环形池容量
+}
+
+// Snapshot 导出当前学到的状态
+func (p *Pool[T]) Snapshot() Snapshot {
+	return Snapshot{
+		CalibratedSize: p.CalibratedSize(),
+		RingCap:        p.pool.Cap(),
+	}
+}
+
+// Restore 用快照覆盖当前状态，值会按本池的 MinSize/MaxSize 与环形池容量上下限截断
+// 快照来自配置不同的旧版本时也是安全的
+func (p *Pool[T]) Restore(s Snapshot) {
+	if s.CalibratedSize > 0 {
+		p.calibratedSz.Store(p.cfg.Load().clamp(s.CalibratedSize))
+	}
+	if s.RingCap > 0 {
+		p.pool.Resize(s.RingCap)
+	}
+}
@@ -0,0 +1,51 @@
+package buffer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestSnapshotRoundTrip 测试导出的快照可以热启动新池
+func TestSnapshotRoundTrip(t *testing.T) {
+	p := NewBufferPool(Options().SetCalibratePeriod(100))
+	for i := 0; i < 1000; i++ {
+		buf := p.Get()
+		buf.Write(make([]byte, 8192))
+		p.Put(buf)
+	}
+
+	data, err := json.Marshal(p.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("snapshot: %s", data)
+
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	p2 := NewBufferPool(Options().SetSnapshot(s))
+	if got := p2.Snapshot(); got != p.Snapshot() {
+		t.Fatalf("restored %+v, want %+v", got, p.Snapshot())
+	}
+
+	buf := p2.Get()
+	if uint64(buf.Cap()) < s.CalibratedSize {
+		t.Fatalf("first buffer cap %d smaller than restored size %d", buf.Cap(), s.CalibratedSize)
+	}
+	p2.Put(buf)
+}
+
+// TestSnapshotClamp 测试快照按新池的上下限截断
+func TestSnapshotClamp(t *testing.T) {
+	p := NewBufferPool(Options().SetMaxSize(4096))
+	p.Restore(Snapshot{CalibratedSize: 1 << 30, RingCap: 1 << 20})
+
+	s := p.Snapshot()
+	if s.CalibratedSize != 4096 {
+		t.Errorf("expected calibrated size clamped to 4096, got %d", s.CalibratedSize)
+	}
+	if s.RingCap != DefaultMaxCapacity {
+		t.Errorf("expected ring cap clamped to %d, got %d", DefaultMaxCapacity, s.RingCap)
+	}
+}
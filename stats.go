@@ -0,0 +1,28 @@
+package buffer
+
+// Stats 池的运行时快照，用于监控和调参
+type Stats struct {
+	CalibratedSize uint64 // 当前校准值，新建对象使用的尺寸
+	RingCap        int    // 环形池当前容量
+	Idle           int    // 环形池中的空闲对象数
+	VictimIdle     int    // GC 分代模式下上一代的空闲对象数
+	Leaks          uint64 // 被 GC 回收但从未 Release 的 Handle 数
+	RingHits       uint64 // 累计从环形池 (含 victim) 取到的次数
+	OverflowHits   uint64 // 累计从溢出层 (sync.Pool) 取到的次数
+	Misses         uint64 // 累计新建的次数
+}
+
+// Stats 返回池的运行时快照
+func (p *Pool[T]) Stats() Stats {
+	ringHits, overflowHits, misses := p.pool.HitStats()
+	return Stats{
+		CalibratedSize: p.CalibratedSize(),
+		RingCap:        p.pool.Cap(),
+		Idle:           p.pool.Idle(),
+		VictimIdle:     p.pool.VictimIdle(),
+		Leaks:          p.Leaks(),
+		RingHits:       ringHits,
+		OverflowHits:   overflowHits,
+		Misses:         misses,
+	}
+}
@@ -0,0 +1,150 @@
+package buffer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// OptionError 非法配置项
+type OptionError struct {
+	Field  string // Option 字段名
+	Value  any    // 非法的值
+	Reason string // 原因
+}
+
+func (e *OptionError) Error() string {
+	return fmt.Sprintf("buffer: invalid option %s=%v: %s", e.Field, e.Value, e.Reason)
+}
+
+// Validate 检查配置是否合理，返回所有问题 (errors.Join 合并，可用 errors.As 取出 *OptionError)
+// 只检查已设置的字段，两两关联的检查要求两个字段都已设置；
+// 需要结合默认值检查时使用 NewE 系列构造函数
+func (o *Option) Validate() error {
+	if o == nil {
+		return nil
+	}
+	var errs []error
+	if o.MaxPercent != nil && !validMaxPercent(*o.MaxPercent) {
+		errs = append(errs, &OptionError{"MaxPercent", *o.MaxPercent, "must be finite and >= 1, otherwise every buffer is discarded"})
+	}
+	if o.CalibratePeriod != nil && *o.CalibratePeriod == 0 {
+		errs = append(errs, &OptionError{"CalibratePeriod", *o.CalibratePeriod, "must be > 0"})
+	}
+	if o.MaxSize != nil && *o.MaxSize == 0 {
+		errs = append(errs, &OptionError{"MaxSize", *o.MaxSize, "must be > 0"})
+	}
+	if o.MinSize != nil && o.MaxSize != nil && *o.MinSize > *o.MaxSize {
+		errs = append(errs, &OptionError{"MinSize", *o.MinSize, fmt.Sprintf("greater than MaxSize %d", *o.MaxSize)})
+	}
+	if o.CalibratedSz != nil && o.MaxSize != nil && *o.CalibratedSz > *o.MaxSize {
+		errs = append(errs, &OptionError{"CalibratedSz", *o.CalibratedSz, fmt.Sprintf("greater than MaxSize %d", *o.MaxSize)})
+	}
+	if o.EmaUpFactor != nil && !validEmaFactor(*o.EmaUpFactor) {
+		errs = append(errs, &OptionError{"EmaUpFactor", *o.EmaUpFactor, "must be in [0, 1)"})
+	}
+	if o.EmaDownFactor != nil && !validEmaFactor(*o.EmaDownFactor) {
+		errs = append(errs, &OptionError{"EmaDownFactor", *o.EmaDownFactor, "must be in [0, 1), 1 would never shrink"})
+	}
+	if o.Align != nil && !validAlign(*o.Align) {
+		errs = append(errs, &OptionError{"Align", *o.Align, "must be 0, 1 or a power of two"})
+	}
+	if o.RingMinCap != nil && *o.RingMinCap < 1 {
+		errs = append(errs, &OptionError{"RingMinCap", *o.RingMinCap, "must be >= 1"})
+	}
+	if o.RingMinCap != nil && o.RingMaxCap != nil && *o.RingMinCap > *o.RingMaxCap {
+		errs = append(errs, &OptionError{"RingMinCap", *o.RingMinCap, fmt.Sprintf("greater than RingMaxCap %d", *o.RingMaxCap)})
+	}
+	return errors.Join(errs...)
+}
+
+// normalize 把非法配置修正为最接近的合法值，不报错的构造函数 (New/NewBufferPool/...) 使用：
+//   - MaxPercent < 1 修正为 1，NaN/+Inf 恢复默认值 1.5
+//   - CalibratePeriod 为 0 修正为 1 (每次 Put 都校准)
+//   - MaxSize 为 0 修正为 1
+//   - MinSize > MaxSize 时 MaxSize 提升为 MinSize
+//   - CalibratedSz 截断到 [MinSize, MaxSize]
+//   - EmaUpFactor/EmaDownFactor 超出 [0, 1) 或为 NaN 时恢复默认值
+//   - Align 不是 2 的幂时向上取到 2 的幂
+func (o *Option) normalize() {
+	if o.Align != nil && !validAlign(*o.Align) {
+		o.SetAlign(1 << bits.Len64(*o.Align))
+	}
+	if o.EmaUpFactor != nil && !validEmaFactor(*o.EmaUpFactor) {
+		o.SetEmaUpFactor(emaUpFactor)
+	}
+	if o.EmaDownFactor != nil && !validEmaFactor(*o.EmaDownFactor) {
+		o.SetEmaDownFactor(emaDownFactor)
+	}
+	if o.MaxPercent != nil && !validMaxPercent(*o.MaxPercent) {
+		if *o.MaxPercent < 1 {
+			o.SetMaxPercent(1)
+		} else {
+			o.SetMaxPercent(1.5) // NaN/+Inf 没有最接近的合法值，恢复默认
+		}
+	}
+	if o.CalibratePeriod != nil && *o.CalibratePeriod == 0 {
+		o.SetCalibratePeriod(1)
+	}
+	if o.MaxSize != nil && *o.MaxSize == 0 {
+		o.SetMaxSize(1)
+	}
+	if o.MinSize != nil && o.MaxSize != nil && *o.MinSize > *o.MaxSize {
+		o.SetMaxSize(*o.MinSize)
+	}
+	if o.CalibratedSz != nil && o.MinSize != nil && o.MaxSize != nil {
+		o.SetCalibratedSz(max(*o.MinSize, min(*o.CalibratedSz, *o.MaxSize)))
+	}
+}
+
+// NewE 与 New 相同，但配置 (合并默认值后) 非法时返回错误，而不是静默修正
+func NewE[T any](makeFunc func(uint64) T, resetFunc func(T) T, statFunc func(T) (uint64, uint64), opts ...*Option) (*Pool[T], error) {
+	if err := validateOptions(opts...); err != nil {
+		return nil, err
+	}
+	return New(makeFunc, resetFunc, statFunc, opts...), nil
+}
+
+// NewBufferPoolE 与 NewBufferPool 相同，但配置非法时返回错误
+func NewBufferPoolE(opts ...*Option) (*Pool[*bytes.Buffer], error) {
+	if err := validateOptions(opts...); err != nil {
+		return nil, err
+	}
+	return NewBufferPool(opts...), nil
+}
+
+// NewBytePoolE 与 NewBytePool 相同，但配置非法时返回错误
+func NewBytePoolE(opts ...*Option) (*Pool[[]byte], error) {
+	if err := validateOptions(opts...); err != nil {
+		return nil, err
+	}
+	return NewBytePool(opts...), nil
+}
+
+// validateOptions 检查用户配置，以及它与默认值的组合 (与构造函数使用同一份合并结果)
+// 默认的 MinSize/CalibratedSz 会先截断到用户的 MaxSize (见 clampDefaults)，
+// 所以 SetMaxSize(512) 这种普通配置不会因为用户没设置的默认值报错；
+// 默认 MaxSize 是防 OOM 的上限，用户的 MinSize/CalibratedSz 超过它仍然报错
+func validateOptions(opts ...*Option) error {
+	opt := defaultOptions(opts...)
+	return opt.Validate()
+}
+
+// validAlign 0 和 1 表示不对齐，其余必须是 2 的幂
+func validAlign(a uint64) bool {
+	return a <= 1 || a&(a-1) == 0
+}
+
+// validMaxPercent 必须是 >= 1 的有限值
+// 用 !(v >= 1) 而不是 v < 1：NaN 的比较恒为 false，FromEnv/flag 的 ParseFloat 可以解析出 "NaN"，
+// 门卫里 uint64(NaN*x) 的结果未定义，丢弃判决会完全失效
+func validMaxPercent(v float64) bool {
+	return v >= 1 && !math.IsInf(v, 1)
+}
+
+// validEmaFactor 必须在 [0, 1) 内，NaN 不在任何区间内
+func validEmaFactor(v float64) bool {
+	return v >= 0 && v < 1
+}
@@ -0,0 +1,121 @@
+package buffer
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestOptionValidate 测试各类非法配置
+func TestOptionValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		opt   *Option
+		field string
+	}{
+		{"MaxPercent<1", Options().SetMaxPercent(0.5), "MaxPercent"},
+		{"CalibratePeriod=0", Options().SetCalibratePeriod(0), "CalibratePeriod"},
+		{"MinSize>MaxSize", Options().SetMinSize(4096).SetMaxSize(1024), "MinSize"},
+		{"CalibratedSz>MaxSize", Options().SetCalibratedSz(8192).SetMaxSize(4096), "CalibratedSz"},
+		{"MaxSize=0", Options().SetMaxSize(0), "MaxSize"},
+		{"Align not power of two", Options().SetAlign(1000), "Align"},
+		{"EmaUpFactor>=1", Options().SetEmaUpFactor(1), "EmaUpFactor"},
+		{"EmaDownFactor<0", Options().SetEmaDownFactor(-0.1), "EmaDownFactor"},
+		{"MaxPercent=NaN", Options().SetMaxPercent(math.NaN()), "MaxPercent"},
+		{"MaxPercent=+Inf", Options().SetMaxPercent(math.Inf(1)), "MaxPercent"},
+		{"EmaUpFactor=NaN", Options().SetEmaUpFactor(math.NaN()), "EmaUpFactor"},
+		{"EmaDownFactor=NaN", Options().SetEmaDownFactor(math.NaN()), "EmaDownFactor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opt.Validate()
+			var oe *OptionError
+			if !errors.As(err, &oe) {
+				t.Fatalf("expected *OptionError, got %v", err)
+			}
+			if oe.Field != tt.field {
+				t.Fatalf("expected field %s, got %s (%v)", tt.field, oe.Field, err)
+			}
+		})
+	}
+
+	if err := Options().SetMinSize(512).SetMaxSize(1024).Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var nilOpt *Option
+	if err := nilOpt.Validate(); err != nil {
+		t.Fatalf("nil option should be valid, got %v", err)
+	}
+}
+
+// TestNewE 测试返回错误的构造函数会结合默认值检查
+func TestNewE(t *testing.T) {
+	// 单独看合法，但与默认 MaxSize (64MB) 冲突
+	if _, err := NewBufferPoolE(Options().SetMinSize(128 << 20)); err == nil {
+		t.Fatal("expected error for MinSize above default MaxSize")
+	}
+	if _, err := NewBytePoolE(Options().SetMaxPercent(0.9)); err == nil {
+		t.Fatal("expected error for MaxPercent < 1")
+	}
+	p, err := NewBufferPoolE(Options().SetMinSize(1024))
+	if err != nil || p == nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 小于默认 MinSize/CalibratedSz 的 MaxSize 是合法配置，默认值先截断
+	p, err = NewBufferPoolE(Options().SetMaxSize(512))
+	if err != nil {
+		t.Fatalf("unexpected error for small MaxSize: %v", err)
+	}
+	if sz := p.CalibratedSize(); sz != 512 {
+		t.Fatalf("expected calibrated size 512, got %d", sz)
+	}
+	if cfg := p.cfg.Load(); cfg.maxSize != 512 {
+		t.Fatalf("expected maxSize 512, got %d", cfg.maxSize)
+	}
+	bp, err := NewBytePoolE(Options().SetMaxSize(256))
+	if err != nil {
+		t.Fatalf("unexpected error for MaxSize below default MinSize: %v", err)
+	}
+	// 校验通过的配置就是实际生效的配置，用户的上限不能被默认 MinSize 抬高
+	if cfg := bp.cfg.Load(); cfg.maxSize != 256 || cfg.minSize != 256 {
+		t.Fatalf("expected maxSize/minSize 256, got %+v", *cfg)
+	}
+	bp, err = NewBytePoolE(Options().SetMaxSize(100))
+	if err != nil {
+		t.Fatalf("unexpected error for MaxSize 100: %v", err)
+	}
+	if c := cap(bp.Get()); c != 100 || bp.cfg.Load().maxSize != 100 {
+		t.Fatalf("expected cap and maxSize 100, got cap %d maxSize %d", c, bp.cfg.Load().maxSize)
+	}
+	// 不报错的构造函数同样保留用户的上限
+	if sp := NewSlicePool[int](Options().SetMaxSize(4)); sp.cfg.Load().maxSize != 4 || cap(sp.Get()) != 4 {
+		t.Fatalf("expected slice pool maxSize 4, got %+v", *sp.cfg.Load())
+	}
+	// 用户显式设置的字段仍然交叉检查
+	if _, err := NewBufferPoolE(Options().SetMaxSize(512).SetCalibratedSz(1024)); err == nil {
+		t.Fatal("expected error for explicit CalibratedSz above MaxSize")
+	}
+}
+
+// TestNewNormalize 测试不报错的构造函数会修正非法配置
+func TestNewNormalize(t *testing.T) {
+	p := NewBytePool(Options().SetMaxPercent(0.5).SetMinSize(4096).SetMaxSize(1024).SetCalibratePeriod(0))
+	cfg := p.cfg.Load()
+	if cfg.maxPercent != 1 || cfg.maxSize != 4096 || cfg.calibratePeriod != 1 {
+		t.Fatalf("unexpected normalized config %+v", *cfg)
+	}
+
+	// NaN 比较恒为 false，必须单独修正，否则门卫里 uint64(NaN*x) 会让丢弃判决失效
+	np := NewBytePool(Options().SetMaxPercent(math.NaN()).SetEmaUpFactor(math.NaN()).SetEmaDownFactor(math.NaN()))
+	if cfg := np.cfg.Load(); cfg.maxPercent != 1.5 || cfg.emaUp != emaUpFactor || cfg.emaDown != emaDownFactor {
+		t.Fatalf("expected NaN options to fall back to defaults, got %+v", *cfg)
+	}
+
+	// MaxPercent 修正为 1 后，容量等于校准值的 buffer 依旧可以复用
+	b := p.Get()
+	p.Put(b)
+	if p.Stats().Idle != 1 {
+		t.Fatal("expected buffer to be pooled after normalization")
+	}
+}
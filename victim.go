@@ -0,0 +1,80 @@
+package buffer
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// -----------------------------------------------------------------------------
+// GC 分代（victim）模式
+// -----------------------------------------------------------------------------
+// 环形池默认会一直持有最多 maxCap 个空闲对象。开启 victim 模式后行为接近 sync.Pool：
+// 每次 GC 把队列中的空闲对象移入 victim，上一代 victim 被丢弃 (触发 OnDrop)，
+// 所以一个对象空闲超过两次 GC 就会被释放；Get 在队列为空时先从 victim 取，再 New。
+
+// gcSentinel 哨兵对象，每次 GC 被判定不可达时 finalizer 运行一次，然后重新挂上 finalizer
+// 含指针字段，避免被 tiny allocator 和其它小对象合并分配而延迟回收
+type gcSentinel struct {
+	stopped *atomic.Bool
+}
+
+// onGC 每次 GC 后在 finalizer goroutine 中调用 fn，直到 stop 被调用
+func onGC(fn func()) (stop func()) {
+	s := &gcSentinel{stopped: new(atomic.Bool)}
+	stopped := s.stopped
+	var finalizer func(*gcSentinel)
+	finalizer = func(s *gcSentinel) {
+		if s.stopped.Load() {
+			return
+		}
+		fn()
+		runtime.SetFinalizer(s, finalizer)
+	}
+	runtime.SetFinalizer(s, finalizer)
+	return func() {
+		stopped.Store(true)
+	}
+}
+
+// EnableVictim 开启 GC 分代模式，返回的 stop 关闭它 (已移入 victim 的对象仍可被 Get 取走)
+// 与 KeepWarm 一样，stop 之前池不会被回收；不要对同一个池重复开启
+func (p *AdaptiveRingPool[T]) EnableVictim() (stop func()) {
+	return onGC(p.rotateVictim)
+}
+
+// rotateVictim 队列中的空闲对象整体移入 victim，丢弃上一代 victim
+func (p *AdaptiveRingPool[T]) rotateVictim() {
+	p.mu.Lock()
+	old := p.victim
+	p.victim = nil
+	if p.count > 0 {
+		p.victim = make([]T, p.count)
+		var zero T
+		for i := range p.victim {
+			idx := (p.head + i) % p.curCap
+			p.victim[i] = p.buffer[idx]
+			p.buffer[idx] = zero
+		}
+		p.head, p.tail, p.count = 0, 0, 0
+	}
+	p.mu.Unlock()
+
+	// 丢弃回调可能是系统调用 (如 munmap)，放到锁外执行
+	if p.OnDrop != nil {
+		for _, obj := range old {
+			p.OnDrop(obj)
+		}
+	}
+}
+
+// VictimIdle 返回 victim 中的空闲对象数
+func (p *AdaptiveRingPool[T]) VictimIdle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.victim)
+}
+
+// EnableVictim 开启 GC 分代模式，空闲超过两次 GC 的对象会被释放，见 AdaptiveRingPool.EnableVictim
+func (p *Pool[T]) EnableVictim() (stop func()) {
+	return p.pool.EnableVictim()
+}
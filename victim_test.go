@@ -0,0 +1,84 @@
+package buffer
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestRotateVictim 测试两代轮换：第一次 GC 移入 victim，第二次 GC 丢弃
+func TestRotateVictim(t *testing.T) {
+	var dropped int
+	r := NewAdaptiveRingPoolWithLimit(4, 16, func() *int { return new(int) })
+	r.OnDrop = func(*int) { dropped++ }
+
+	objs := []*int{new(int), new(int), new(int)}
+	for _, o := range objs {
+		r.Put(o)
+	}
+
+	r.rotateVictim()
+	if r.Idle() != 0 || r.VictimIdle() != 3 {
+		t.Fatalf("after first rotation: idle %d victim %d", r.Idle(), r.VictimIdle())
+	}
+
+	// 队列为空时从 victim 取
+	got := r.Get()
+	found := false
+	for _, o := range objs {
+		found = found || o == got
+	}
+	if !found {
+		t.Fatal("expected Get to fall back to the victim")
+	}
+
+	r.rotateVictim()
+	if r.VictimIdle() != 0 || dropped != 2 {
+		t.Fatalf("after second rotation: victim %d dropped %d", r.VictimIdle(), dropped)
+	}
+}
+
+// TestEnableVictim 测试真实 GC 触发轮换，stop 后不再轮换
+func TestEnableVictim(t *testing.T) {
+	p := NewBufferPool()
+	stop := p.EnableVictim()
+
+	p.Put(p.Get())
+	runtime.GC()
+	waitFor(t, func() bool { return p.Stats().VictimIdle == 1 })
+
+	stop()
+	p.Put(p.Get()) // 从 victim 取出后放回主队列
+	runtime.GC()
+	runtime.GC()
+	time.Sleep(10 * time.Millisecond) // 给 finalizer goroutine 运行的机会
+	if st := p.Stats(); st.Idle != 1 || st.VictimIdle != 0 {
+		t.Fatalf("expected no rotation after stop: %+v", st)
+	}
+}
+
+// TestEvictVictim 测试 Evict 同时作用于 victim
+func TestEvictVictim(t *testing.T) {
+	r := NewAdaptiveRingPoolWithLimit(4, 16, func() []byte { return nil })
+	r.Put(make([]byte, 8))
+	r.Put(make([]byte, 1024))
+	r.rotateVictim()
+
+	if n := r.Evict(func(b []byte) bool { return cap(b) > 64 }); n != 1 {
+		t.Fatalf("expected 1 evicted, got %d", n)
+	}
+	if r.VictimIdle() != 1 {
+		t.Fatalf("expected 1 left in victim, got %d", r.VictimIdle())
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}